@@ -1,83 +1,53 @@
 package main
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"os"
 
+	"github.com/drewslam/goloxTreeInterpreter/golox"
 	"github.com/drewslam/goloxTreeInterpreter/interpreter"
 	"github.com/drewslam/goloxTreeInterpreter/loxDebug"
 	"github.com/drewslam/goloxTreeInterpreter/loxError"
-	"github.com/drewslam/goloxTreeInterpreter/parser"
-	"github.com/drewslam/goloxTreeInterpreter/resolver"
-	"github.com/drewslam/goloxTreeInterpreter/scanner"
 )
 
 type Lox struct {
-	interpreter *interpreter.Interpreter
+	vm *golox.VM
 }
 
 func NewLox() *Lox {
+	vm := golox.New()
+	// Scripts run from the command line are trusted with the file system.
+	vm.SetFileAccess(interpreter.FileAccess{Enabled: true})
 	return &Lox{
-		interpreter: interpreter.NewInterpreter(),
+		vm: vm,
 	}
 }
 
 func (l *Lox) runFile(path string) error {
-	bytes, err := os.ReadFile(path)
-	if err != nil {
-		return fmt.Errorf("Failed to read file: %v", err)
-	}
-	result := l.run(string(bytes))
-	if result == nil {
-		return nil
-	}
-	return result
+	return l.vm.RunFile(path)
 }
 
 func (l *Lox) runPrompt() {
-	reader := bufio.NewReader(os.Stdin)
+	interpreter := l.vm.Interpreter()
+	reader := interpreter.Stdin()
 
 	for {
-		fmt.Print("> ")
+		fmt.Fprint(interpreter.Stdout(), "> ")
 		line, err := reader.ReadString('\n')
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+			fmt.Fprintf(interpreter.Stderr(), "Error reading input: %v\n", err)
 			return
 		}
 		if line == "\n" {
 			continue
 		}
-		if err := l.run(line); err != nil {
-			fmt.Fprintf(os.Stderr, "Error executing line: %v\n", err)
+		if _, err := l.vm.Eval(line); err != nil {
+			fmt.Fprintf(interpreter.Stderr(), "Error executing line: %v\n", err)
 		}
 	}
 }
 
-func (l *Lox) run(source string) *loxError.LoxError {
-	scanner := scanner.NewScanner(source)
-	tokens, err := scanner.ScanTokens()
-	if err != nil {
-		return err
-	}
-
-	parser := parser.NewParser(tokens)
-	statements, err := parser.Parse()
-	if err != nil {
-		return err
-	}
-
-	resolver := resolver.NewResolver(l.interpreter)
-	err = resolver.Resolve(statements)
-	if err != nil {
-		return err
-	}
-
-	l.interpreter.Interpret(statements)
-	return nil
-}
-
 func main() {
 	loxDebug.InitializeLogger()
 	defer loxDebug.CloseLogger()
@@ -93,13 +63,14 @@ func main() {
 			// Determine exit code based on error type
 			var loxErr *loxError.LoxError
 			if errors.As(err, &loxErr) {
-				loxError.ReportError(loxErr)
+				loxError.ReportError(lox.vm.Interpreter().Stderr(), loxErr)
 				if loxErr.IsFatal {
 					os.Exit(70) // Runtime error
 				} else {
 					os.Exit(65) // Syntax error
 				}
 			} else {
+				fmt.Fprintln(lox.vm.Interpreter().Stderr(), err)
 				os.Exit(65)
 			}
 		}
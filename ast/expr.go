@@ -12,8 +12,13 @@ type ExprVisitor interface {
 	VisitCallExpr(expr *Call) interface{}
 	VisitGetExpr(expr *Get) interface{}
 	VisitGroupingExpr(expr *Grouping) interface{}
+	VisitIndexExpr(expr *Index) interface{}
+	VisitIndexSetExpr(expr *IndexSet) interface{}
+	VisitLambdaExpr(expr *Lambda) interface{}
+	VisitListExpr(expr *List) interface{}
 	VisitLiteralExpr(expr *Literal) interface{}
 	VisitLogicalExpr(expr *Logical) interface{}
+	VisitMapExpr(expr *Map) interface{}
 	VisitSetExpr(expr *Set) interface{}
 	VisitSuperExpr(expr *Super) interface{}
 	VisitThisExpr(expr *This) interface{}
@@ -87,6 +92,62 @@ func (expr *Grouping) Accept(visitor ExprVisitor) interface{} {
 	return visitor.VisitGroupingExpr(expr)
 }
 
+// Index: Subscript read: "object[index]"
+type Index struct {
+	Object  Expr
+	Bracket token.Token
+	Index   Expr
+}
+
+func (expr *Index) Accept(visitor ExprVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitIndexExpr(expr)
+}
+
+// IndexSet: Subscript assignment: "object[index] = value"
+type IndexSet struct {
+	Object  Expr
+	Bracket token.Token
+	Index   Expr
+	Value   Expr
+}
+
+func (expr *IndexSet) Accept(visitor ExprVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitIndexSetExpr(expr)
+}
+
+// Lambda: Anonymous function: "fun (params) { body }" or "(params) => expr".
+// Function has an empty name.
+type Lambda struct {
+	Keyword  token.Token
+	Function *Function
+}
+
+func (expr *Lambda) Accept(visitor ExprVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitLambdaExpr(expr)
+}
+
+// List: List literal: "[a, b, c]"
+type List struct {
+	Bracket  token.Token
+	Elements []Expr
+}
+
+func (expr *List) Accept(visitor ExprVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitListExpr(expr)
+}
+
 // Literal: Literal value: Number, String, true, false, nil
 type Literal struct {
 	Value interface{}
@@ -113,6 +174,20 @@ func (expr *Logical) Accept(visitor ExprVisitor) interface{} {
 	return visitor.VisitLogicalExpr(expr)
 }
 
+// Map: Map literal: "{key: value, ...}"
+type Map struct {
+	Brace  token.Token
+	Keys   []Expr
+	Values []Expr
+}
+
+func (expr *Map) Accept(visitor ExprVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitMapExpr(expr)
+}
+
 // Set
 type Set struct {
 	Object Expr
@@ -10,12 +10,19 @@ type Stmt interface {
 
 type StmtVisitor interface {
 	VisitBlockStmt(stmt *Block) interface{}
+	VisitBreakStmt(stmt *Break) interface{}
 	VisitClassStmt(stmt *Class) interface{}
+	VisitContinueStmt(stmt *Continue) interface{}
 	VisitExpressionStmt(stmt *Expression) interface{}
 	VisitFunctionStmt(stmt *Function) interface{}
 	VisitIfStmt(stmt *If) interface{}
+	VisitImportStmt(stmt *Import) interface{}
+	VisitInterfaceStmt(stmt *Interface) interface{}
 	VisitPrintStmt(stmt *Print) interface{}
 	VisitReturnStmt(stmt *Return) interface{}
+	VisitThrowStmt(stmt *Throw) interface{}
+	VisitTraitStmt(stmt *Trait) interface{}
+	VisitTryStmt(stmt *Try) interface{}
 	VisitVarStmt(stmt *Var) interface{}
 	VisitWhileStmt(stmt *While) interface{}
 }
@@ -36,11 +43,28 @@ func NewBlockStmt(statements []Stmt) *Block {
 	return &Block{Statements: statements}
 }
 
-// Class type
+// Break type
+type Break struct {
+	Keyword token.Token
+}
+
+func (stmt *Break) Accept(visitor StmtVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitBreakStmt(stmt)
+}
+
+// Class type. ClassMethods are the methods declared with 'class', which are
+// called on the class itself rather than on an instance. Traits are the
+// traits listed after 'with' and Interfaces those after 'implements'.
 type Class struct {
-	Name       token.Token
-	Superclass *Variable
-	Methods    []*Function
+	Name         token.Token
+	Superclass   *Variable
+	Traits       []*Variable
+	Interfaces   []*Variable
+	Methods      []*Function
+	ClassMethods []*Function
 }
 
 func (stmt *Class) Accept(visitor StmtVisitor) interface{} {
@@ -59,6 +83,18 @@ func NewClassStmt(name token.Token, superclass *Variable, methods []*Function) *
 	}
 }
 
+// Continue type
+type Continue struct {
+	Keyword token.Token
+}
+
+func (stmt *Continue) Accept(visitor StmtVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitContinueStmt(stmt)
+}
+
 // Expression type
 type Expression struct {
 	Expr Expr
@@ -75,11 +111,15 @@ func NewExpressionStmt(expr Expr) *Expression {
 	return &Expression{Expr: expr}
 }
 
-// Function type
+// Function type. IsGetter marks a method declared without a parameter
+// list, which runs when the property is read. IsAbstract marks a method
+// declared without a body, in an abstract class or an interface.
 type Function struct {
-	Name   token.Token
-	Params []token.Token
-	Body   []Stmt
+	Name       token.Token
+	Params     []token.Token
+	Body       []Stmt
+	IsGetter   bool
+	IsAbstract bool
 }
 
 func (stmt *Function) Accept(visitor StmtVisitor) interface{} {
@@ -119,6 +159,35 @@ func NewIfStmt(condition Expr, thenBranch Stmt, elseBranch Stmt) *If {
 	}
 }
 
+// Import type. "import path as alias;" binds the module to Alias, while
+// "from path import names;" binds each of Names and leaves Alias empty.
+type Import struct {
+	Keyword token.Token
+	Path    token.Token
+	Alias   token.Token
+	Names   []token.Token
+}
+
+func (stmt *Import) Accept(visitor StmtVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitImportStmt(stmt)
+}
+
+// Interface type. Methods are the required methods, which have no bodies.
+type Interface struct {
+	Name    token.Token
+	Methods []*Function
+}
+
+func (stmt *Interface) Accept(visitor StmtVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitInterfaceStmt(stmt)
+}
+
 // Print type
 type Print struct {
 	Expr Expr
@@ -148,6 +217,47 @@ func (stmt *Return) Accept(visitor StmtVisitor) interface{} {
 	return visitor.VisitReturnStmt(stmt)
 }
 
+// Throw type
+type Throw struct {
+	Keyword token.Token
+	Value   Expr
+}
+
+func (stmt *Throw) Accept(visitor StmtVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitThrowStmt(stmt)
+}
+
+// Trait type
+type Trait struct {
+	Name    token.Token
+	Methods []*Function
+}
+
+func (stmt *Trait) Accept(visitor StmtVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitTraitStmt(stmt)
+}
+
+// Try type. Catch and Finally are nil when their clause is absent.
+type Try struct {
+	Body      *Block
+	CatchName token.Token
+	Catch     *Block
+	Finally   *Block
+}
+
+func (stmt *Try) Accept(visitor StmtVisitor) interface{} {
+	if visitor == nil {
+		return nil
+	}
+	return visitor.VisitTryStmt(stmt)
+}
+
 // Variable type
 type Var struct {
 	Name        token.Token
@@ -168,10 +278,14 @@ func NewVarStmt(name token.Token, initializer Expr) *Var {
 	}
 }
 
-// While type
+// While type. Increment is set for desugared for loops and runs after the
+// body on every iteration, including those ended by continue. Keyword is
+// the 'while' or 'for' token.
 type While struct {
+	Keyword   token.Token
 	Condition Expr
 	Body      Stmt
+	Increment Expr
 }
 
 func (stmt *While) Accept(visitor StmtVisitor) interface{} {
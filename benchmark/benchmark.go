@@ -0,0 +1,39 @@
+// Command benchmark times Lox scripts through the golox package, which
+// unlike the CLI does not write a debug log.
+//
+// Usage: go run ./benchmark [-n runs] script.lox...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/drewslam/goloxTreeInterpreter/golox"
+)
+
+func main() {
+	runs := flag.Int("n", 5, "number of runs per script")
+	flag.Parse()
+
+	for _, path := range flag.Args() {
+		var best time.Duration
+		for run := 0; run < *runs; run++ {
+			vm := golox.New()
+			vm.SetStdout(io.Discard)
+
+			start := time.Now()
+			if err := vm.RunFile(path); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			elapsed := time.Since(start)
+			if run == 0 || elapsed < best {
+				best = elapsed
+			}
+		}
+		fmt.Printf("%s: best of %d runs %v\n", path, *runs, best)
+	}
+}
@@ -0,0 +1,31 @@
+package completion
+
+// Type records why a statement finished early.
+type Type int
+
+const (
+	RETURN Type = iota
+	BREAK
+	CONTINUE
+)
+
+// Completion is returned by statement visitors to unwind enclosing blocks,
+// loops and function bodies without panicking. A statement that finishes
+// normally returns nil instead.
+type Completion struct {
+	Type  Type
+	Value interface{}
+}
+
+// Break and Continue carry no value, so one instance of each is shared.
+var (
+	Break    = &Completion{Type: BREAK}
+	Continue = &Completion{Type: CONTINUE}
+)
+
+func NewReturn(value interface{}) *Completion {
+	return &Completion{
+		Type:  RETURN,
+		Value: value,
+	}
+}
@@ -3,14 +3,19 @@ package environment
 import (
 	"fmt"
 
-	"github.com/drewslam/goloxTreeInterpreter/loxDebug"
 	"github.com/drewslam/goloxTreeInterpreter/loxError"
 	"github.com/drewslam/goloxTreeInterpreter/token"
 )
 
+// Environment holds variables for one scope. Global environments store
+// variables by name in Values; every nested environment stores locals in
+// Slots, indexed in declaration order as assigned by the resolver. Each
+// module has its own global environment, enclosed by one holding the
+// natives, so Get and Assign by name fall through to the natives.
 type Environment struct {
 	Enclosing *Environment
 	Values    map[string]interface{}
+	Slots     []interface{}
 }
 
 func NewEnvironment(enclosing ...*Environment) *Environment {
@@ -19,12 +24,41 @@ func NewEnvironment(enclosing ...*Environment) *Environment {
 		parent = enclosing[0]
 	}
 
+	if parent == nil {
+		return &Environment{
+			Values: make(map[string]interface{}),
+		}
+	}
+
 	return &Environment{
 		Enclosing: parent,
+	}
+}
+
+// NewGlobalEnvironment creates an environment that stores variables by
+// name, such as the top level of a module, inside enclosing.
+func NewGlobalEnvironment(enclosing *Environment) *Environment {
+	return &Environment{
+		Enclosing: enclosing,
 		Values:    make(map[string]interface{}),
 	}
 }
 
+// IsGlobal reports whether variables are stored by name.
+func (e *Environment) IsGlobal() bool {
+	return e.Values != nil
+}
+
+// GlobalScope returns the nearest enclosing global environment, which holds
+// the globals of the module the code was declared in.
+func (e *Environment) GlobalScope() *Environment {
+	environment := e
+	for environment.Values == nil {
+		environment = environment.Enclosing
+	}
+	return environment
+}
+
 func (e *Environment) Get(name token.Token) (interface{}, *loxError.LoxError) {
 	if value, exists := e.Values[name.Lexeme]; exists {
 		return value, nil
@@ -54,47 +88,30 @@ func (e *Environment) Assign(name token.Token, value interface{}) *loxError.LoxE
 	return loxError.NewRuntimeError(name, fmt.Sprintf("%d", name.Line), errMsg)
 }
 
+// Define declares a variable. Globals are stored under name; locals take the
+// next slot, so declarations must happen in the order the resolver saw them.
 func (e *Environment) Define(name string, value interface{}) {
-	e.Values[name] = value
+	if e.Values != nil {
+		e.Values[name] = value
+		return
+	}
+	e.Slots = append(e.Slots, value)
 }
 
-func (e *Environment) Ancestor(distance int) (*Environment, error) {
+func (e *Environment) Ancestor(distance int) *Environment {
 	environment := e
 	for i := 0; i < distance; i++ {
-		if environment.Enclosing == nil {
-			return nil, fmt.Errorf("Environment chain not deep enough")
-		}
 		environment = environment.Enclosing
 	}
-	return environment, nil
+	return environment
 }
 
-func (e *Environment) GetAt(distance int, name string) (interface{}, error) {
-	loxDebug.LogDebug("Attempting to get '%s' at distance %d\n", name, distance)
-
-	ancestor, err := e.Ancestor(distance)
-	if err != nil {
-		loxDebug.LogError("Error retrieving ancestor at distance %d: %v\n", distance, err)
-		return nil, err
-	}
-	loxDebug.LogDebug("Retrieved ancestor environment: %+v\n", ancestor.Values)
-
-	value, exists := ancestor.Values[name]
-	if !exists {
-		loxDebug.LogError("Variable '%s' not found in ancestor environment at distance %d\n", name, distance)
-		return nil, fmt.Errorf("Undefined variable '%s'", name)
-	}
-
-	loxDebug.LogInfo("Variable '%s' found at distance %d: %v\n", name, distance, value)
-	return value, nil
+// GetAt reads the local in slot of the environment distance scopes up.
+func (e *Environment) GetAt(distance int, slot int) interface{} {
+	return e.Ancestor(distance).Slots[slot]
 }
 
-func (e *Environment) AssignAt(distance int, name token.Token, value interface{}) error {
-	ancestor, err := e.Ancestor(distance)
-	if err != nil {
-		return err
-	}
-
-	ancestor.Values[name.Lexeme] = value
-	return nil
+// AssignAt writes the local in slot of the environment distance scopes up.
+func (e *Environment) AssignAt(distance int, slot int, value interface{}) {
+	e.Ancestor(distance).Slots[slot] = value
 }
@@ -0,0 +1,177 @@
+// Package golox exposes the scanner, parser, resolver and interpreter
+// pipeline so Lox programs can be embedded in Go programs.
+package golox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/drewslam/goloxTreeInterpreter/ast"
+	"github.com/drewslam/goloxTreeInterpreter/interpreter"
+	"github.com/drewslam/goloxTreeInterpreter/loxCallable"
+	"github.com/drewslam/goloxTreeInterpreter/loxError"
+	"github.com/drewslam/goloxTreeInterpreter/token"
+)
+
+// Value is a Lox value as seen from Go: nil, bool, float64, string or one
+// of the object types from the object package.
+type Value = interface{}
+
+// VM runs Lox source against a single interpreter, so globals defined by
+// one call to Eval are visible to the next.
+type VM struct {
+	interpreter *interpreter.Interpreter
+}
+
+// New returns a VM whose globals hold only the native functions.
+func New() *VM {
+	return &VM{
+		interpreter: interpreter.NewInterpreter(),
+	}
+}
+
+// Interpreter returns the underlying interpreter.
+func (vm *VM) Interpreter() *interpreter.Interpreter {
+	return vm.interpreter
+}
+
+// SetStdout sets where print statements write.
+func (vm *VM) SetStdout(w io.Writer) {
+	vm.interpreter.SetStdout(w)
+}
+
+// SetStderr sets where diagnostics are written.
+func (vm *VM) SetStderr(w io.Writer) {
+	vm.interpreter.SetStderr(w)
+}
+
+// SetStdin sets where program input is read from.
+func (vm *VM) SetStdin(r io.Reader) {
+	vm.interpreter.SetStdin(r)
+}
+
+// SetContext sets a context whose cancellation stops running programs.
+func (vm *VM) SetContext(ctx context.Context) {
+	vm.interpreter.SetContext(ctx)
+}
+
+// SetLimits bounds the steps, call depth and time each Eval, RunFile or
+// Call may use. Errors caused by a limit satisfy errors.Is with
+// loxError.ErrStepLimit, loxError.ErrCallDepthLimit, or the context's error.
+func (vm *VM) SetLimits(limits interpreter.Limits) {
+	vm.interpreter.SetLimits(limits)
+}
+
+// SetFileAccess enables the file natives such as readFile and writeFile, and
+// import, optionally confined to a root directory. They are disabled by
+// default.
+func (vm *VM) SetFileAccess(access interpreter.FileAccess) {
+	vm.interpreter.SetFileAccess(access)
+}
+
+// SeedRandom makes math.random and math.randomInt reproducible.
+func (vm *VM) SeedRandom(seed int64) {
+	vm.interpreter.SeedRandom(seed)
+}
+
+// Eval runs source and returns the value of its final statement when that
+// statement is an expression, or nil otherwise.
+func (vm *VM) Eval(source string) (Value, error) {
+	statements, err := vm.compile(source)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := vm.interpreter.InterpretValue(statements)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// RunFile reads the script at path and runs it.
+func (vm *VM) RunFile(path string) error {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read file: %v", err)
+	}
+
+	statements, lerr := vm.compile(string(bytes))
+	if lerr != nil {
+		return lerr
+	}
+	vm.interpreter.SetScriptPath(path)
+	if lerr := vm.interpreter.Interpret(statements); lerr != nil {
+		return lerr
+	}
+	return nil
+}
+
+// Call looks up a global function or class by name and calls it with args.
+// Go integers and float32 values are converted to Lox numbers.
+func (vm *VM) Call(fnName string, args ...Value) (Value, error) {
+	callee, lerr := vm.interpreter.Globals.Get(token.Token{Lexeme: fnName})
+	if lerr != nil {
+		return nil, lerr
+	}
+
+	function, ok := callee.(loxCallable.LoxCallable)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not callable", fnName)
+	}
+
+	arguments := make([]interface{}, len(args))
+	for i, arg := range args {
+		arguments[i] = toLox(arg)
+	}
+
+	result, lerr := vm.interpreter.Call(function, arguments)
+	if lerr != nil {
+		return nil, lerr
+	}
+	return result, nil
+}
+
+// DefineNative exposes a Go function to Lox code as a global named name.
+// See loxCallable.NewReflectedNative for the supported signatures.
+func (vm *VM) DefineNative(name string, fn any) error {
+	return vm.interpreter.DefineNative(name, fn)
+}
+
+// compile scans, parses and resolves source, returning the first error.
+func (vm *VM) compile(source string) ([]ast.Stmt, *loxError.LoxError) {
+	return vm.interpreter.Compile(source)
+}
+
+// toLox converts Go numeric types to the float64 Lox uses for numbers.
+func toLox(value Value) Value {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int8:
+		return float64(v)
+	case int16:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint8:
+		return float64(v)
+	case uint16:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case uintptr:
+		return float64(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
@@ -0,0 +1,199 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/drewslam/goloxTreeInterpreter/environment"
+	"github.com/drewslam/goloxTreeInterpreter/loxCallable"
+	"github.com/drewslam/goloxTreeInterpreter/object"
+)
+
+// FileAccess controls what the file natives and imports may touch. The zero
+// value denies all access. When Root is set, paths must lie inside it and
+// relative paths are taken relative to it rather than to the working
+// directory.
+type FileAccess struct {
+	Enabled bool
+	Root    string
+}
+
+var errFileAccessDisabled = errors.New("File access is disabled.")
+
+// SetFileAccess sets what readFile, writeFile and the other file natives,
+// and import, are allowed to do.
+func (i *Interpreter) SetFileAccess(access FileAccess) {
+	i.fileAccess = access
+}
+
+// checkPath returns the path a file native should use for path, or an error
+// if the interpreter's FileAccess doesn't allow it.
+func (i *Interpreter) checkPath(path string) (string, error) {
+	access := i.fileAccess
+	if !access.Enabled {
+		return "", errFileAccessDisabled
+	}
+	if access.Root == "" {
+		return path, nil
+	}
+
+	root, err := filepath.Abs(access.Root)
+	if err != nil {
+		return "", fmt.Errorf("Invalid file access root: %v.", err)
+	}
+	if real, err := filepath.EvalSymlinks(root); err == nil {
+		root = real
+	}
+
+	resolved := path
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(root, resolved)
+	}
+	resolved = filepath.Clean(resolved)
+
+	// Follow symlinks in the part of the path that exists, so links can't
+	// lead out of the root.
+	if !isWithin(root, realPath(resolved)) {
+		return "", fmt.Errorf("Path '%s' is outside the allowed directory.", path)
+	}
+	return resolved, nil
+}
+
+// realPath resolves symlinks in the longest existing prefix of path.
+func realPath(path string) string {
+	rest := ""
+	for {
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			return filepath.Join(real, rest)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(path, rest)
+		}
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
+
+func isWithin(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// registerFileNatives defines the file and directory natives in env. They
+// are always defined, but fail unless SetFileAccess enabled them.
+func (i *Interpreter) registerFileNatives(env *environment.Environment) {
+	loxCallable.DefineReflected(env, "readFile", func(path string) (string, error) {
+		path, err := i.checkPath(path)
+		if err != nil {
+			return "", err
+		}
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("Can't read file: %v.", err)
+		}
+		return string(bytes), nil
+	})
+	loxCallable.DefineReflected(env, "writeFile", func(path string, content string) error {
+		path, err := i.checkPath(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("Can't write file: %v.", err)
+		}
+		return nil
+	})
+	loxCallable.DefineReflected(env, "appendFile", func(path string, content string) error {
+		path, err := i.checkPath(path)
+		if err != nil {
+			return err
+		}
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("Can't append to file: %v.", err)
+		}
+		defer file.Close()
+		if _, err := file.WriteString(content); err != nil {
+			return fmt.Errorf("Can't append to file: %v.", err)
+		}
+		return nil
+	})
+	// readLines returns a list of lines without their line endings.
+	loxCallable.DefineReflected(env, "readLines", func(path string) (any, error) {
+		path, err := i.checkPath(path)
+		if err != nil {
+			return nil, err
+		}
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Can't read file: %v.", err)
+		}
+		var lines []interface{}
+		text := strings.TrimSuffix(string(bytes), "\n")
+		if text != "" {
+			for _, line := range strings.Split(text, "\n") {
+				lines = append(lines, strings.TrimSuffix(line, "\r"))
+			}
+		}
+		return object.NewLoxList(lines), nil
+	})
+	loxCallable.DefineReflected(env, "exists", func(path string) (bool, error) {
+		path, err := i.checkPath(path)
+		if err != nil {
+			return false, err
+		}
+		_, err = os.Stat(path)
+		return err == nil, nil
+	})
+	// listDir returns the sorted names of the entries in a directory.
+	loxCallable.DefineReflected(env, "listDir", func(path string) (any, error) {
+		path, err := i.checkPath(path)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("Can't list directory: %v.", err)
+		}
+		names := make([]string, len(entries))
+		for index, entry := range entries {
+			names[index] = entry.Name()
+		}
+		sort.Strings(names)
+		elements := make([]interface{}, len(names))
+		for index, name := range names {
+			elements[index] = name
+		}
+		return object.NewLoxList(elements), nil
+	})
+	// mkdir creates a directory along with any missing parents.
+	loxCallable.DefineReflected(env, "mkdir", func(path string) error {
+		path, err := i.checkPath(path)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			return fmt.Errorf("Can't create directory: %v.", err)
+		}
+		return nil
+	})
+	// remove deletes a file or an empty directory.
+	loxCallable.DefineReflected(env, "remove", func(path string) error {
+		path, err := i.checkPath(path)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("Can't remove: %v.", err)
+		}
+		return nil
+	})
+}
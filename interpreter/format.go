@@ -0,0 +1,209 @@
+package interpreter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format renders template for the format and printf natives, replacing
+// each placeholder with the next argument:
+//
+//	{}         the argument as print shows it
+//	{:spec}    the argument formatted by spec
+//	{{ and }}  literal braces
+//
+// A spec is [align][width][.precision][type]. align is '<' (left) or '>'
+// (right); numbers are right-aligned and everything else left-aligned by
+// default. width is the minimum width in characters. Neither width nor
+// precision may exceed 1000. type is one of:
+//
+//	f  fixed-point number with precision decimals (6 by default)
+//	e  number in scientific notation with precision decimals
+//	d  integer
+//	x  integer in hexadecimal
+//	s  the value as print shows it, cut to precision characters
+//
+// Without a type, a number with a precision uses f and anything else is
+// formatted as s. Every argument must be used exactly once.
+func (i *Interpreter) Format(template string, arguments []interface{}) (string, error) {
+	var out strings.Builder
+	next := 0
+
+	for pos := 0; pos < len(template); pos++ {
+		c := template[pos]
+		switch {
+		case c == '{' && strings.HasPrefix(template[pos+1:], "{"):
+			out.WriteByte('{')
+			pos++
+		case c == '}' && strings.HasPrefix(template[pos+1:], "}"):
+			out.WriteByte('}')
+			pos++
+		case c == '{':
+			end := strings.IndexByte(template[pos:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("Unclosed '{' in format string.")
+			}
+			field := template[pos+1 : pos+end]
+			pos += end
+
+			if next >= len(arguments) {
+				return "", fmt.Errorf("Format string needs more than %d arguments.", len(arguments))
+			}
+			text, err := i.formatField(field, arguments[next])
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(text)
+			next++
+		case c == '}':
+			return "", fmt.Errorf("Unmatched '}' in format string.")
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	if next < len(arguments) {
+		return "", fmt.Errorf("Format string uses %d of %d arguments.", next, len(arguments))
+	}
+	return out.String(), nil
+}
+
+// formatSpec is a parsed placeholder spec. precision is -1 when absent and
+// verb is 0 when no type was given.
+type formatSpec struct {
+	align     byte
+	width     int
+	precision int
+	verb      byte
+}
+
+func parseFormatSpec(text string) (formatSpec, error) {
+	spec := formatSpec{precision: -1}
+	invalid := fmt.Errorf("Invalid format spec '%s'.", text)
+	rest := text
+
+	if rest != "" && (rest[0] == '<' || rest[0] == '>') {
+		spec.align = rest[0]
+		rest = rest[1:]
+	}
+
+	digits := leadingDigits(rest)
+	if digits != "" {
+		width, err := parseFormatSize(digits)
+		if err != nil {
+			return spec, err
+		}
+		spec.width = width
+		rest = rest[len(digits):]
+	}
+
+	if strings.HasPrefix(rest, ".") {
+		digits = leadingDigits(rest[1:])
+		if digits == "" {
+			return spec, invalid
+		}
+		precision, err := parseFormatSize(digits)
+		if err != nil {
+			return spec, err
+		}
+		spec.precision = precision
+		rest = rest[1+len(digits):]
+	}
+
+	if rest != "" {
+		if len(rest) > 1 || !strings.Contains("fedxs", rest) {
+			return spec, invalid
+		}
+		spec.verb = rest[0]
+	}
+	return spec, nil
+}
+
+// maxFormatSize bounds widths and precisions, so a spec can't make format
+// allocate huge strings.
+const maxFormatSize = 1000
+
+// parseFormatSize parses the digits of a width or precision.
+func parseFormatSize(digits string) (int, error) {
+	size, err := strconv.Atoi(digits)
+	if err != nil || size > maxFormatSize {
+		return 0, fmt.Errorf("Format width and precision can't exceed %d.", maxFormatSize)
+	}
+	return size, nil
+}
+
+func leadingDigits(text string) string {
+	end := 0
+	for end < len(text) && text[end] >= '0' && text[end] <= '9' {
+		end++
+	}
+	return text[:end]
+}
+
+// formatField renders one argument for the placeholder {field}.
+func (i *Interpreter) formatField(field string, value interface{}) (string, error) {
+	if field == "" {
+		return i.Stringify(value), nil
+	}
+	if field[0] != ':' {
+		return "", fmt.Errorf("Invalid placeholder '{%s}'.", field)
+	}
+	spec, err := parseFormatSpec(field[1:])
+	if err != nil {
+		return "", err
+	}
+
+	num, isNumber := value.(float64)
+	verb := spec.verb
+	if verb == 0 {
+		verb = 's'
+		if isNumber && spec.precision >= 0 {
+			verb = 'f'
+		}
+	}
+
+	var text string
+	switch verb {
+	case 'f', 'e':
+		if !isNumber {
+			return "", fmt.Errorf("Format type '%c' needs a number.", verb)
+		}
+		precision := spec.precision
+		if precision < 0 {
+			precision = 6
+		}
+		text = strconv.FormatFloat(num, verb, precision, 64)
+	case 'd', 'x':
+		if !isNumber || num != float64(int64(num)) {
+			return "", fmt.Errorf("Format type '%c' needs an integer.", verb)
+		}
+		base := 10
+		if verb == 'x' {
+			base = 16
+		}
+		text = strconv.FormatInt(int64(num), base)
+	case 's':
+		text = i.Stringify(value)
+		if spec.precision >= 0 && utf8.RuneCountInString(text) > spec.precision {
+			text = string([]rune(text)[:spec.precision])
+		}
+	}
+
+	padding := spec.width - utf8.RuneCountInString(text)
+	if padding <= 0 {
+		return text, nil
+	}
+	align := spec.align
+	if align == 0 {
+		align = '<'
+		if isNumber {
+			align = '>'
+		}
+	}
+	if align == '>' {
+		return strings.Repeat(" ", padding) + text, nil
+	}
+	return text + strings.Repeat(" ", padding), nil
+}
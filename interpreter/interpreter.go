@@ -1,60 +1,154 @@
 package interpreter
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
+	"math/rand"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/drewslam/goloxTreeInterpreter/ast"
+	"github.com/drewslam/goloxTreeInterpreter/completion"
 	"github.com/drewslam/goloxTreeInterpreter/environment"
 	"github.com/drewslam/goloxTreeInterpreter/loxCallable"
 	"github.com/drewslam/goloxTreeInterpreter/loxDebug"
 	"github.com/drewslam/goloxTreeInterpreter/loxError"
 	"github.com/drewslam/goloxTreeInterpreter/object"
-	"github.com/drewslam/goloxTreeInterpreter/returnValue"
 	"github.com/drewslam/goloxTreeInterpreter/token"
 )
 
+// local is where the resolver found a local variable: how many environments
+// up from the current one, and which slot within it.
+type local struct {
+	depth int
+	slot  int
+}
+
 type Interpreter struct {
 	exprVisitor ast.ExprVisitor
 	stmtVisitor ast.StmtVisitor
 	Globals     *environment.Environment
-	locals      map[ast.Expr]int
+	builtins    *environment.Environment
+	locals      map[ast.Expr]local
+	privates    map[ast.Expr]*ast.Class
+	converting  map[*object.LoxInstance]bool
+	rendering   map[interface{}]bool
 	environment *environment.Environment
+	stdout      io.Writer
+	stderr      io.Writer
+	stdin       *bufio.Reader
+	ctx         context.Context
+	limits      Limits
+	runCtx      context.Context
+	cancelRun   context.CancelFunc
+	running     int
+	steps       int
+	callDepth   int
+	at          token.Token
+	errorClass  *object.LoxClass
+	modules     map[string]*object.LoxModule
+	loading     map[string]bool
+	moduleDirs  map[*environment.Environment]string
+	random      *rand.Rand
+	fileAccess  FileAccess
 }
 
 func NewInterpreter() *Interpreter {
-	globalEnv := environment.NewEnvironment()
+	// Natives live in their own environment enclosing the globals of the
+	// main program and of every imported module.
+	builtins := environment.NewEnvironment()
+	globalEnv := environment.NewGlobalEnvironment(builtins)
+
+	loxCallable.RegisterNatives(builtins)
+
+	// Runtime errors caught by a catch clause are instances of Error.
+	errorClass := &object.LoxClass{
+		Name:    "Error",
+		Methods: make(map[string]*object.LoxFunction),
+	}
+	builtins.Define(errorClass.Name, errorClass)
 
-	loxCallable.RegisterNatives(globalEnv)
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	mathEnv := environment.NewEnvironment()
+	loxCallable.RegisterMath(mathEnv, random)
+	builtins.Define("math", object.NewLoxModule("math", mathEnv))
 
-	return &Interpreter{
+	i := &Interpreter{
 		Globals:     globalEnv,
+		builtins:    builtins,
 		environment: globalEnv,
-		locals:      make(map[ast.Expr]int),
+		locals:      make(map[ast.Expr]local),
+		privates:    make(map[ast.Expr]*ast.Class),
+		converting:  make(map[*object.LoxInstance]bool),
+		rendering:   make(map[interface{}]bool),
+		stdout:      os.Stdout,
+		stderr:      os.Stderr,
+		stdin:       bufio.NewReader(os.Stdin),
+		ctx:         context.Background(),
+		errorClass:  errorClass,
+		modules:     make(map[string]*object.LoxModule),
+		loading:     make(map[string]bool),
+		moduleDirs:  make(map[*environment.Environment]string),
+		random:      random,
 	}
+	i.registerNatives(builtins)
+	i.registerFileNatives(builtins)
+	return i
+}
+
+// SetStdout sets where program output such as print statements is written.
+func (i *Interpreter) SetStdout(w io.Writer) {
+	i.stdout = w
+}
+
+// SetStderr sets where diagnostics are written.
+func (i *Interpreter) SetStderr(w io.Writer) {
+	i.stderr = w
+}
+
+// SetStdin sets where program input is read from.
+func (i *Interpreter) SetStdin(r io.Reader) {
+	if reader, ok := r.(*bufio.Reader); ok {
+		i.stdin = reader
+		return
+	}
+	i.stdin = bufio.NewReader(r)
+}
+
+// SeedRandom reseeds the generator behind math.random and math.randomInt,
+// the same as calling math.seed(seed) from Lox.
+func (i *Interpreter) SeedRandom(seed int64) {
+	i.random.Seed(seed)
+}
+
+func (i *Interpreter) Stdout() io.Writer {
+	return i.stdout
 }
 
-func (i *Interpreter) StoreResolution(expr ast.Expr, depth int) {
-	i.locals[expr] = depth
+func (i *Interpreter) Stderr() io.Writer {
+	return i.stderr
 }
 
-func (i *Interpreter) Interpret(statements []ast.Stmt) {
+// Stdin returns the buffered input reader. Everything reading program input
+// must share it so buffered data is not lost between readers.
+func (i *Interpreter) Stdin() *bufio.Reader {
+	return i.stdin
+}
+
+// Interpret executes the statements in order, returning the first runtime
+// error encountered instead of exiting the process. After an error the
+// interpreter is back in the scope it started in, so it can keep running.
+func (i *Interpreter) Interpret(statements []ast.Stmt) (err *loxError.LoxError) {
+	i.beginRun()
+	defer i.endRun()
+	previous := i.environment
 	defer func() {
 		if r := recover(); r != nil {
-			switch v := r.(type) {
-			case *loxError.LoxError:
-				loxError.ReportError(v)
-				if v.IsFatal {
-					os.Exit(1)
-				}
-				return
-			case *returnValue.ReturnValue:
-				if v.Value != nil {
-					fmt.Println(v.Value)
-				}
-			default:
-				panic(r) // Re-panic if it's not a RuntimeError
-			}
+			i.environment = previous
+			err = loxError.HandleRecoveredError(r)
 		}
 	}()
 
@@ -62,22 +156,118 @@ func (i *Interpreter) Interpret(statements []ast.Stmt) {
 		result := i.execute(stmt)
 		loxDebug.LogInfo("Environment after executing %T: %+v\n", stmt, i.environment.Values)
 		if result != nil {
-			loxDebug.LogDebug("Executiong returned unexpected value: %v\n", result)
+			loxDebug.LogDebug("Execution completed abruptly: %v\n", result)
+		}
+	}
+	return nil
+}
+
+// InterpretValue executes the statements like Interpret and returns the
+// value of the final statement when it is an expression, or nil otherwise.
+// Everything happens in one run, so the limits cover all of it.
+func (i *Interpreter) InterpretValue(statements []ast.Stmt) (interface{}, *loxError.LoxError) {
+	i.beginRun()
+	defer i.endRun()
+
+	var last *ast.Expression
+	if len(statements) > 0 {
+		if expr, ok := statements[len(statements)-1].(*ast.Expression); ok {
+			last = expr
+			statements = statements[:len(statements)-1]
 		}
 	}
+
+	if err := i.Interpret(statements); err != nil {
+		return nil, err
+	}
+	if last == nil {
+		return nil, nil
+	}
+	return i.Evaluate(last.Expr)
+}
+
+// Evaluate evaluates a single expression in the current environment,
+// returning its value or the runtime error it raised.
+func (i *Interpreter) Evaluate(expr ast.Expr) (result interface{}, err *loxError.LoxError) {
+	i.beginRun()
+	defer i.endRun()
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = loxError.HandleRecoveredError(r)
+		}
+	}()
+
+	return i.evaluate(expr), nil
+}
+
+// Call invokes a callable from host code with already evaluated arguments.
+func (i *Interpreter) Call(callee loxCallable.LoxCallable, arguments []interface{}) (result interface{}, err *loxError.LoxError) {
+	i.beginRun()
+	defer i.endRun()
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = loxError.HandleRecoveredError(r)
+		}
+	}()
+
+	if !loxCallable.CheckArity(callee, len(arguments)) {
+		message := fmt.Sprintf("Expected %d arguments but got %d.", callee.Arity(), len(arguments))
+		return nil, loxError.NewRuntimeError(token.Token{}, callee.String(), message)
+	}
+
+	return callee.Call(i, arguments), nil
+}
+
+// DefineNative defines a global, visible to every module, bound to a Go
+// function. fn may already be a LoxCallable; otherwise it is adapted with
+// loxCallable.NewReflectedNative.
+func (i *Interpreter) DefineNative(name string, fn any) error {
+	if callable, ok := fn.(loxCallable.LoxCallable); ok {
+		i.builtins.Define(name, callable)
+		return nil
+	}
+
+	native, err := loxCallable.NewReflectedNative(name, fn)
+	if err != nil {
+		return err
+	}
+	i.builtins.Define(name, native)
+	return nil
 }
 
 func (i *Interpreter) execute(stmt ast.Stmt) interface{} {
+	if at, ok := statementToken(stmt); ok {
+		i.at = at
+	}
+	i.step()
 	result := stmt.Accept(i)
 	loxDebug.LogDebug("Executing: %T -> result: %v\n", stmt, result)
 	return result
 }
 
-func (i *Interpreter) Resolve(expr ast.Expr, depth int) {
-	if i.locals == nil {
-		panic("Interpreter.locals is nil!")
-	}
-	i.locals[expr] = depth
+// Resolve records that expr refers to the local in slot of the environment
+// depth scopes up. Expressions never resolved refer to globals of the module
+// they appear in.
+func (i *Interpreter) Resolve(expr ast.Expr, depth int, slot int) {
+	i.locals[expr] = local{depth: depth, slot: slot}
+}
+
+// ResolvePrivate records that the private member accessed by expr belongs to
+// the class declared by owner.
+func (i *Interpreter) ResolvePrivate(expr ast.Expr, owner *ast.Class) {
+	i.privates[expr] = owner
+}
+
+// CallGetter runs getter for a property read through name. Getters are
+// called without parentheses, so this is where they count toward the call
+// depth limit.
+func (i *Interpreter) CallGetter(getter loxCallable.LoxCallable, name token.Token) interface{} {
+	i.enterCall(name)
+	result := getter.Call(i, nil)
+	i.exitCall()
+	return result
 }
 
 func (i *Interpreter) GetGlobals() *environment.Environment {
@@ -90,21 +280,10 @@ func (i *Interpreter) ExecuteBlock(statements []ast.Stmt, environment *environme
 
 	defer func() { i.environment = previous }()
 
-	defer func() {
-		if r := recover(); r != nil {
-			if returnValue, ok := r.(*returnValue.ReturnValue); ok {
-				i.environment = previous
-				panic(returnValue)
-			}
-			panic(r)
-		}
-	}()
-
 	for _, statement := range statements {
-		result := i.execute(statement)
-
-		if returnVal, ok := result.(*returnValue.ReturnValue); ok {
-			return returnVal.Value
+		// Stop at the first abrupt completion and hand it to the caller.
+		if result := i.execute(statement); result != nil {
+			return result
 		}
 	}
 	return nil
@@ -113,11 +292,11 @@ func (i *Interpreter) ExecuteBlock(statements []ast.Stmt, environment *environme
 var _ loxCallable.Interpreter = &Interpreter{}
 
 func (i *Interpreter) VisitBlockStmt(stmt *ast.Block) interface{} {
-	err := i.ExecuteBlock(stmt.Statements, environment.NewEnvironment(i.environment))
-	if err != nil {
-		return err
-	}
-	return nil
+	return i.ExecuteBlock(stmt.Statements, environment.NewEnvironment(i.environment))
+}
+
+func (i *Interpreter) VisitBreakStmt(stmt *ast.Break) interface{} {
+	return completion.Break
 }
 
 func (i *Interpreter) VisitClassStmt(stmt *ast.Class) interface{} {
@@ -138,14 +317,34 @@ func (i *Interpreter) VisitClassStmt(stmt *ast.Class) interface{} {
 		superclass = klass
 	}
 
-	i.environment.Define(stmt.Name.Lexeme, nil)
+	traits := make([]*object.LoxTrait, len(stmt.Traits))
+	for index, name := range stmt.Traits {
+		trait, ok := i.evaluate(name).(*object.LoxTrait)
+		if !ok {
+			panic(loxError.NewRuntimeError(name.Name, name.Name.Lexeme, "'"+name.Name.Lexeme+"' is not a trait."))
+		}
+		traits[index] = trait
+	}
+
+	interfaces := make([]*object.LoxInterface, len(stmt.Interfaces))
+	for index, name := range stmt.Interfaces {
+		iface, ok := i.evaluate(name).(*object.LoxInterface)
+		if !ok {
+			panic(loxError.NewRuntimeError(name.Name, name.Name.Lexeme, "'"+name.Name.Lexeme+"' is not an interface."))
+		}
+		interfaces[index] = iface
+	}
+
+	// Methods the class defines override those of its traits, which
+	// override those inherited from the superclass. Trait conflicts are
+	// reported before the environment for super is pushed.
+	methods := i.traitMethods(stmt, traits)
 
 	if stmt.Superclass != nil {
 		i.environment = environment.NewEnvironment(i.environment)
 		i.environment.Define("super", superclass)
 	}
 
-	methods := make(map[string]*object.LoxFunction)
 	for _, method := range stmt.Methods {
 		function := object.NewLoxFunction(method, i.environment, method.Name.Lexeme == "init")
 		methods[method.Name.Lexeme] = function
@@ -153,19 +352,36 @@ func (i *Interpreter) VisitClassStmt(stmt *ast.Class) interface{} {
 
 	// sc := superclass.(*object.LoxClass)
 	klass := &object.LoxClass{
-		Name:       stmt.Name.Lexeme,
-		Superclass: superclass,
-		Methods:    methods,
+		Name:        stmt.Name.Lexeme,
+		Superclass:  superclass,
+		Methods:     methods,
+		Traits:      traits,
+		Interfaces:  interfaces,
+		Declaration: stmt,
 	}
+	klass.Abstract = klass.AbstractMethods()
 
 	if superclass != nil {
 		i.environment = i.environment.Enclosing
 	}
 
-	i.environment.Assign(stmt.Name, klass)
+	for index, iface := range interfaces {
+		i.checkConformance(klass, iface, stmt.Interfaces[index].Name)
+	}
+
+	klass.ClassMethods = make(map[string]*object.LoxFunction)
+	for _, method := range stmt.ClassMethods {
+		klass.ClassMethods[method.Name.Lexeme] = object.NewLoxFunction(method, i.environment, false)
+	}
+
+	i.environment.Define(stmt.Name.Lexeme, klass)
 	return nil
 }
 
+func (i *Interpreter) VisitContinueStmt(stmt *ast.Continue) interface{} {
+	return completion.Continue
+}
+
 func (i *Interpreter) evaluate(expr ast.Expr) interface{} {
 	if expr == nil {
 		err := loxError.NewRuntimeError(token.Token{Line: 0}, "", "Tried to evaluate a nil expression.")
@@ -175,12 +391,7 @@ func (i *Interpreter) evaluate(expr ast.Expr) interface{} {
 
 	loxDebug.LogInfo("Evaluating expression: %T\n", expr)
 
-	defer func() {
-		if r := recover(); r != nil {
-			loxError.HandleRecoveredError(r)
-		}
-	}()
-
+	i.step()
 	result := expr.Accept(i)
 	loxDebug.LogInfo("Expression result: %v (type: %T)\n", result, result)
 	return result
@@ -203,9 +414,9 @@ func (i *Interpreter) VisitFunctionStmt(stmt *ast.Function) interface{} {
 
 func (i *Interpreter) VisitIfStmt(stmt *ast.If) interface{} {
 	if i.isTruthy(i.evaluate(stmt.Condition)) {
-		i.execute(stmt.ThenBranch)
+		return i.execute(stmt.ThenBranch)
 	} else if stmt.ElseBranch != nil {
-		i.execute(stmt.ElseBranch)
+		return i.execute(stmt.ElseBranch)
 	}
 	return nil
 }
@@ -213,7 +424,7 @@ func (i *Interpreter) VisitIfStmt(stmt *ast.If) interface{} {
 func (i *Interpreter) VisitPrintStmt(stmt *ast.Print) interface{} {
 	value := i.evaluate(stmt.Expr)
 	loxDebug.LogInfo("Printing value: %v\n", value)
-	fmt.Println(i.stringify(value))
+	fmt.Fprintln(i.stdout, i.Stringify(value))
 	return nil
 }
 
@@ -222,8 +433,173 @@ func (i *Interpreter) VisitReturnStmt(stmt *ast.Return) interface{} {
 	if stmt.Value != nil {
 		value = i.evaluate(stmt.Value)
 	}
-	loxDebug.LogDebug("Panic with return value:", value)
-	panic(&returnValue.ReturnValue{Value: value})
+	loxDebug.LogDebug("Returning value: %v", value)
+	return completion.NewReturn(value)
+}
+
+// traitMethods collects the methods of the traits a class uses. A method
+// provided by two traits is an error unless the class defines it itself.
+func (i *Interpreter) traitMethods(stmt *ast.Class, traits []*object.LoxTrait) map[string]*object.LoxFunction {
+	own := make(map[string]bool)
+	for _, method := range stmt.Methods {
+		own[method.Name.Lexeme] = true
+	}
+
+	methods := make(map[string]*object.LoxFunction)
+	providers := make(map[string]*object.LoxTrait)
+	for index, trait := range traits {
+		for name, method := range trait.Methods {
+			if own[name] {
+				continue
+			}
+			if other, exists := providers[name]; exists {
+				at := stmt.Traits[index].Name
+				panic(loxError.NewRuntimeError(at, at.Lexeme, fmt.Sprintf("Method '%s' is provided by both traits '%s' and '%s'.", name, other.Name, trait.Name)))
+			}
+			providers[name] = trait
+			methods[name] = method
+		}
+	}
+	return methods
+}
+
+// checkConformance checks that class defines every method of iface, with
+// the same parameters. Abstract methods count, leaving them to subclasses.
+func (i *Interpreter) checkConformance(class *object.LoxClass, iface *object.LoxInterface, at token.Token) {
+	for _, required := range iface.Methods {
+		name := required.Name.Lexeme
+		method, exists := class.FindMethod(name)
+		if !exists {
+			panic(loxError.NewRuntimeError(at, at.Lexeme, fmt.Sprintf("Class %s doesn't implement '%s' from interface %s.", class.Name, name, iface.Name)))
+		}
+		if method.Declaration.IsGetter != required.IsGetter {
+			kind := "a method"
+			if required.IsGetter {
+				kind = "a getter"
+			}
+			panic(loxError.NewRuntimeError(at, at.Lexeme, fmt.Sprintf("Interface %s requires '%s' to be %s.", iface.Name, name, kind)))
+		}
+		if method.Arity() != len(required.Params) {
+			panic(loxError.NewRuntimeError(at, at.Lexeme, fmt.Sprintf("Interface %s requires '%s' to take %d parameters but it takes %d.", iface.Name, name, len(required.Params), method.Arity())))
+		}
+	}
+}
+
+func (i *Interpreter) VisitInterfaceStmt(stmt *ast.Interface) interface{} {
+	i.environment.Define(stmt.Name.Lexeme, &object.LoxInterface{
+		Name:    stmt.Name.Lexeme,
+		Methods: stmt.Methods,
+	})
+	return nil
+}
+
+func (i *Interpreter) VisitTraitStmt(stmt *ast.Trait) interface{} {
+	methods := make(map[string]*object.LoxFunction)
+	for _, method := range stmt.Methods {
+		methods[method.Name.Lexeme] = object.NewLoxFunction(method, i.environment, method.Name.Lexeme == "init")
+	}
+
+	i.environment.Define(stmt.Name.Lexeme, &object.LoxTrait{
+		Name:    stmt.Name.Lexeme,
+		Methods: methods,
+	})
+	return nil
+}
+
+func (i *Interpreter) VisitThrowStmt(stmt *ast.Throw) interface{} {
+	value := i.evaluate(stmt.Value)
+
+	// Report instances carrying a message, such as rethrown runtime
+	// errors, by that message if nothing catches them.
+	message := i.Stringify(value)
+	if instance, ok := value.(*object.LoxInstance); ok {
+		if text, ok := instance.Fields["message"].(string); ok {
+			message = text
+		}
+	}
+	panic(loxError.NewThrowError(stmt.Keyword, value, message))
+}
+
+func (i *Interpreter) VisitTryStmt(stmt *ast.Try) (result interface{}) {
+	if stmt.Finally != nil {
+		env := i.environment
+		callDepth := i.callDepth
+
+		defer func() {
+			r := recover()
+			if err, ok := r.(*loxError.LoxError); ok && err.IsLimit() {
+				// Limits stop the program without running more code.
+				panic(r)
+			}
+			if r != nil {
+				// Unwind the scopes and calls the error escaped from, as
+				// executeCatching does.
+				i.environment = env
+				i.callDepth = callDepth
+			}
+
+			// An abrupt completion of the finally block replaces whatever
+			// the try or catch did, including a pending error.
+			if completed := i.execute(stmt.Finally); completed != nil {
+				result = completed
+				return
+			}
+			if r != nil {
+				panic(r)
+			}
+		}()
+	}
+
+	if stmt.Catch == nil {
+		return i.execute(stmt.Body)
+	}
+
+	result, caught := i.executeCatching(stmt.Body)
+	if caught == nil {
+		return result
+	}
+
+	env := environment.NewEnvironment(i.environment)
+	env.Define(stmt.CatchName.Lexeme, i.caughtValue(caught))
+	return i.ExecuteBlock([]ast.Stmt{stmt.Catch}, env)
+}
+
+// executeCatching executes stmt and recovers any runtime error it raises
+// other than an exhausted limit.
+func (i *Interpreter) executeCatching(stmt ast.Stmt) (result interface{}, caught *loxError.LoxError) {
+	env := i.environment
+	callDepth := i.callDepth
+
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(*loxError.LoxError)
+			if !ok || err.IsLimit() {
+				panic(r)
+			}
+			i.environment = env
+			i.callDepth = callDepth
+			result = nil
+			caught = err
+		}
+	}()
+
+	return i.execute(stmt), nil
+}
+
+// caughtValue is what a catch clause binds: the thrown value, or an Error
+// instance describing a runtime error.
+func (i *Interpreter) caughtValue(err *loxError.LoxError) interface{} {
+	if err.Thrown {
+		return err.Value
+	}
+
+	return &object.LoxInstance{
+		Klass: i.errorClass,
+		Fields: map[string]interface{}{
+			"message": err.Message,
+			"line":    float64(err.Line),
+		},
+	}
 }
 
 func (i *Interpreter) VisitVarStmt(stmt *ast.Var) interface{} {
@@ -238,37 +614,31 @@ func (i *Interpreter) VisitVarStmt(stmt *ast.Var) interface{} {
 }
 
 func (i *Interpreter) VisitWhileStmt(stmt *ast.While) interface{} {
-	// previous := i.environment
-
-	defer func() {
-		if r := recover(); r != nil {
-			// i.environment = previous
-			// Re-panic to propagate the return
-			panic(r)
-		}
-	}()
-
 	for i.isTruthy(i.evaluate(stmt.Condition)) {
-		result := i.execute(stmt.Body)
+		if result := i.execute(stmt.Body); result != nil {
+			c := result.(*completion.Completion)
+			if c.Type == completion.BREAK {
+				break
+			}
+			if c.Type != completion.CONTINUE {
+				return result
+			}
+		}
 
-		if returnVal, ok := result.(*returnValue.ReturnValue); ok {
-			// i.environment = previous
-			return returnVal
+		if stmt.Increment != nil {
+			i.evaluate(stmt.Increment)
 		}
 	}
-
-	// i.environment = previous
 	return nil
 }
 
 func (i *Interpreter) VisitAssignExpr(expr *ast.Assign) interface{} {
 	value := i.evaluate(expr.Value)
 
-	if distance, exists := i.locals[expr]; exists {
-		i.environment.AssignAt(distance, expr.Name, value)
+	if local, exists := i.locals[expr]; exists {
+		i.environment.AssignAt(local.depth, local.slot, value)
 	} else {
-		// i.Globals.Assign(expr.Name, value)
-		err := i.environment.Assign(expr.Name, value)
+		err := i.environment.GlobalScope().Assign(expr.Name, value)
 		if err != nil {
 			panic(err)
 		}
@@ -281,11 +651,29 @@ func (i *Interpreter) VisitBinaryExpr(expr *ast.Binary) interface{} {
 	left := i.evaluate(expr.Left)
 	right := i.evaluate(expr.Right)
 
+	// Instances can overload operators with methods such as __add__.
+	if _, ok := left.(*object.LoxInstance); ok {
+		if result, ok := i.binaryOperator(expr.Operator, left, right); ok {
+			return result
+		}
+	}
+
 	switch expr.Operator.Type {
 	case token.BANG_EQUAL:
 		return !i.isEqual(left, right)
 	case token.EQUAL_EQUAL:
 		return i.isEqual(left, right)
+	case token.IS:
+		instance, isInstance := left.(*object.LoxInstance)
+		switch v := right.(type) {
+		case *object.LoxClass:
+			return isInstance && instance.Klass.IsSubclassOf(v)
+		case *object.LoxTrait:
+			return isInstance && instance.Klass.Includes(v)
+		case *object.LoxInterface:
+			return isInstance && instance.Klass.Implements(v)
+		}
+		panic(loxError.NewRuntimeError(expr.Operator, expr.Operator.Lexeme, "Right operand of 'is' must be a class, trait or interface."))
 	case token.GREATER:
 		i.checkNumberOperands(expr.Operator, left, right)
 		return left.(float64) > right.(float64)
@@ -311,6 +699,14 @@ func (i *Interpreter) VisitBinaryExpr(expr *ast.Binary) interface{} {
 			if rightVal, ok := right.(string); ok {
 				return leftVal + rightVal
 			}
+			if rightVal, ok := i.instanceString(right); ok {
+				return leftVal + rightVal
+			}
+		}
+		if rightVal, ok := right.(string); ok {
+			if leftVal, ok := i.instanceString(left); ok {
+				return leftVal + rightVal
+			}
 		}
 		err := loxError.NewRuntimeError(expr.Operator, expr.Operator.Lexeme, "Operands must be two numbers or two strings.")
 		panic(err)
@@ -344,23 +740,48 @@ func (i *Interpreter) VisitCallExpr(expr *ast.Call) interface{} {
 		panic(loxError.NewRuntimeError(expr.Paren, expr.Paren.Lexeme, "Can only call functions and classes."))
 	}
 
-	if len(arguments) != function.Arity() {
+	if !loxCallable.CheckArity(function, len(arguments)) {
 		message := fmt.Sprintf("Expected %d arguments but got %d.", function.Arity(), len(arguments))
 		panic(loxError.NewRuntimeError(expr.Paren, expr.Paren.Lexeme, message))
 	}
 
+	if native, ok := function.(*loxCallable.NativeFunction); ok {
+		result, err := native.Invoke(i, arguments)
+		if err != nil {
+			panic(loxError.NewRuntimeError(expr.Paren, native.Name, err.Error()))
+		}
+		return result
+	}
+
+	i.enterCall(expr.Paren)
 	result := function.Call(i, arguments)
+	i.exitCall()
 	loxDebug.LogDebug("Function returned: %v (type: %T)\n", result, result)
 	return result
 }
 
 func (i *Interpreter) VisitGetExpr(expr *ast.Get) interface{} {
 	objekt := i.evaluate(expr.Object)
-	if instance, ok := objekt.(*object.LoxInstance); ok {
-		return instance.Get(expr.Name)
+	if expr.Name.Type == token.PRIVATE_IDENTIFIER {
+		// The resolver only allows private access through 'this'.
+		return objekt.(*object.LoxInstance).GetPrivate(i, i.privates[expr], expr.Name)
+	}
+	switch v := objekt.(type) {
+	case *object.LoxInstance:
+		return v.Get(i, expr.Name)
+	case *object.LoxList:
+		return v.Get(expr.Name)
+	case *object.LoxMap:
+		return v.Get(expr.Name)
+	case *object.LoxModule:
+		return v.Get(expr.Name)
+	case *object.LoxClass:
+		return v.Get(i, expr.Name)
+	case string:
+		return object.StringMethod(v, expr.Name)
 	}
 
-	return loxError.NewRuntimeError(expr.Name, expr.Name.Lexeme, "Only instances have properties.")
+	panic(loxError.NewRuntimeError(expr.Name, expr.Name.Lexeme, "Only instances have properties."))
 }
 
 func (i *Interpreter) VisitGroupingExpr(expr *ast.Grouping) interface{} {
@@ -368,6 +789,62 @@ func (i *Interpreter) VisitGroupingExpr(expr *ast.Grouping) interface{} {
 	return i.evaluate(expr.Expression)
 }
 
+func (i *Interpreter) VisitIndexExpr(expr *ast.Index) interface{} {
+	objekt := i.evaluate(expr.Object)
+	index := i.evaluate(expr.Index)
+
+	var value interface{}
+	var err error
+	switch v := objekt.(type) {
+	case *object.LoxList:
+		value, err = v.GetAt(index)
+	case *object.LoxMap:
+		value, err = v.GetKey(index)
+	case string:
+		value, err = object.StringAt(v, index)
+	default:
+		panic(loxError.NewRuntimeError(expr.Bracket, expr.Bracket.Lexeme, "Only lists, maps and strings can be indexed."))
+	}
+
+	if err != nil {
+		panic(loxError.NewRuntimeError(expr.Bracket, expr.Bracket.Lexeme, err.Error()))
+	}
+	return value
+}
+
+func (i *Interpreter) VisitIndexSetExpr(expr *ast.IndexSet) interface{} {
+	objekt := i.evaluate(expr.Object)
+	index := i.evaluate(expr.Index)
+	value := i.evaluate(expr.Value)
+
+	var err error
+	switch v := objekt.(type) {
+	case *object.LoxList:
+		err = v.SetAt(index, value)
+	case *object.LoxMap:
+		err = v.SetKey(index, value)
+	default:
+		panic(loxError.NewRuntimeError(expr.Bracket, expr.Bracket.Lexeme, "Only lists and maps can be indexed."))
+	}
+
+	if err != nil {
+		panic(loxError.NewRuntimeError(expr.Bracket, expr.Bracket.Lexeme, err.Error()))
+	}
+	return value
+}
+
+func (i *Interpreter) VisitLambdaExpr(expr *ast.Lambda) interface{} {
+	return object.NewLoxFunction(expr.Function, i.environment, false)
+}
+
+func (i *Interpreter) VisitListExpr(expr *ast.List) interface{} {
+	elements := make([]interface{}, len(expr.Elements))
+	for index, element := range expr.Elements {
+		elements[index] = i.evaluate(element)
+	}
+	return object.NewLoxList(elements)
+}
+
 func (i *Interpreter) VisitLiteralExpr(expr *ast.Literal) interface{} {
 	// Handle literal expressions
 	return expr.Value
@@ -390,44 +867,50 @@ func (i *Interpreter) VisitLogicalExpr(expr *ast.Logical) interface{} {
 	return i.evaluate(expr.Right)
 }
 
+func (i *Interpreter) VisitMapExpr(expr *ast.Map) interface{} {
+	m := object.NewLoxMap()
+	for index, key := range expr.Keys {
+		k := i.evaluate(key)
+		v := i.evaluate(expr.Values[index])
+		if err := m.SetKey(k, v); err != nil {
+			panic(loxError.NewRuntimeError(expr.Brace, expr.Brace.Lexeme, err.Error()))
+		}
+	}
+	return m
+}
+
 func (i *Interpreter) VisitSetExpr(expr *ast.Set) interface{} {
 	objekt := i.evaluate(expr.Object)
 
 	if _, ok := objekt.(*object.LoxInstance); !ok {
-		return loxError.NewRuntimeError(expr.Name, expr.Name.Lexeme, "Only instances have fields.")
+		panic(loxError.NewRuntimeError(expr.Name, expr.Name.Lexeme, "Only instances have fields."))
 	}
 
 	value := i.evaluate(expr.Value)
+	if expr.Name.Type == token.PRIVATE_IDENTIFIER {
+		objekt.(*object.LoxInstance).SetPrivate(i.privates[expr], expr.Name, value)
+		return value
+	}
 	objekt.(*object.LoxInstance).Set(expr.Name, value)
 	return value
 }
 
 func (i *Interpreter) VisitSuperExpr(expr *ast.Super) interface{} {
-	distance, ok := i.locals[expr]
+	local, ok := i.locals[expr]
 	if !ok {
 		er := loxError.NewRuntimeError(expr.Keyword, "super", "Cannot resolve 'super' distance.")
 		panic(er)
 		//loxError.ReportAndPanic(er)
 	}
-	superclass, err := i.environment.GetAt(distance, "super")
-	if err != nil {
-		er := loxError.NewRuntimeError(expr.Keyword, "super", "Undefined superclass reference.")
-		panic(er)
-		// loxError.ReportAndPanic(er)
-	}
-	sc, ok := superclass.(*object.LoxClass)
+	sc, ok := i.environment.GetAt(local.depth, local.slot).(*object.LoxClass)
 	if !ok {
 		er := loxError.NewRuntimeError(expr.Keyword, "super", "'super' does not refer to a class.")
 		panic(er)
 		//loxError.ReportAndPanic(er)
 	}
-	objekt, err := i.environment.GetAt(distance-1, "this")
-	if err != nil {
-		er := loxError.NewRuntimeError(expr.Keyword, "this", "Undefined 'this' reference.")
-		panic(er)
-		//loxError.ReportAndPanic(er)
-	}
-	obj, ok := objekt.(*object.LoxInstance)
+	// "this" is always the only slot of the environment just inside the one
+	// holding "super".
+	obj, ok := i.environment.GetAt(local.depth-1, 0).(*object.LoxInstance)
 	if !ok {
 		er := loxError.NewRuntimeError(expr.Keyword, "this", "'this' is not bound to an instance.")
 		panic(er)
@@ -439,6 +922,9 @@ func (i *Interpreter) VisitSuperExpr(expr *ast.Super) interface{} {
 		panic(er)
 		//loxError.ReportAndPanic(er)
 	}
+	if method.Declaration.IsGetter {
+		return i.CallGetter(method.Bind(obj), expr.Method)
+	}
 	return method.Bind(obj)
 }
 
@@ -454,6 +940,9 @@ func (i *Interpreter) VisitUnaryExpr(expr *ast.Unary) interface{} {
 	case token.BANG:
 		return !i.isTruthy(right)
 	case token.MINUS:
+		if result, ok := i.callOperator(expr.Operator, right, "__neg__"); ok {
+			return result
+		}
 		i.checkNumberOperand(expr.Operator, right)
 		return -right.(float64)
 	}
@@ -467,49 +956,17 @@ func (i *Interpreter) VisitVariableExpr(expr *ast.Variable) interface{} {
 }
 
 func (i *Interpreter) lookUpVariable(name token.Token, expr ast.Expr) interface{} {
-	if i.environment != nil {
-		val, err := i.environment.Get(name)
-		if err == nil {
-			return val
-		}
-	}
-
-	distance, exists := i.locals[expr]
-
-	if !exists {
-		for lookupExpr, lookupDistance := range i.locals {
-			if varExpr, ok := lookupExpr.(*ast.Variable); ok &&
-				varExpr.Name.Lexeme == name.Lexeme {
-				distance = lookupDistance
-				exists = true
-				break
-			}
-		}
-	}
-
-	if exists {
-		loxDebug.LogInfo("Looking up local variable '%s' at distance %d\n", name.Lexeme, distance)
-		res, err := i.environment.GetAt(distance, name.Lexeme)
-		if err != nil {
-			loxDebug.LogError("Error retrieving local variable '%s': %v\n", name.Lexeme, err)
-			err := loxError.NewRuntimeError(name, fmt.Sprintf("%d", name.Line), "Undefined local variable '"+name.Lexeme+"'")
-			panic(err)
-			// loxError.ReportAndPanic(err)
-		}
-		loxDebug.LogDebug("Local variable '%s' resolved to: %v\n", name.Lexeme, res)
-		return res
+	if local, exists := i.locals[expr]; exists {
+		return i.environment.GetAt(local.depth, local.slot)
 	}
 
-	// Fallback to globals
-	loxDebug.LogDebug("Variable '%s' not found locally. Checking globals\n", name.Lexeme)
-	res, err := i.Globals.Get(name)
+	res, err := i.environment.GlobalScope().Get(name)
 	if err != nil {
 		loxDebug.LogError("Error retrieving global variable '%s': %v\n", name.Lexeme, err)
 		err := loxError.NewRuntimeError(name, fmt.Sprintf("%d", name.Line), "Undefined variable '"+name.Lexeme+"'")
 		panic(err)
 		//loxError.ReportAndPanic(err)
 	}
-	loxDebug.LogDebug("Global variable '%s' resolved to: %v\n", name.Lexeme, res)
 	return res
 }
 
@@ -550,16 +1007,92 @@ func (i *Interpreter) isEqual(a interface{}, b interface{}) bool {
 	return a == b
 }
 
-// stringify converts an evaluated object into a human-readable string
-func (i *Interpreter) stringify(object interface{}) string {
-	if object == nil {
+// Stringify converts an evaluated object into a human-readable string, the
+// way print shows it. A list or map nested inside itself is shown as [...]
+// or {...}.
+func (i *Interpreter) Stringify(value interface{}) string {
+	if value == nil {
 		return "nil"
 	}
 
-	if val, ok := object.(float64); ok {
+	if val, ok := value.(float64); ok {
 		text := fmt.Sprintf("%g", val)
 		return text
 	}
 
-	return fmt.Sprintf("%v", object)
+	if list, ok := value.(*object.LoxList); ok {
+		if i.rendering[list] {
+			return "[...]"
+		}
+		i.rendering[list] = true
+		defer delete(i.rendering, list)
+
+		parts := make([]string, len(list.Elements))
+		for index, element := range list.Elements {
+			parts[index] = i.stringifyElement(element)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	}
+
+	if m, ok := value.(*object.LoxMap); ok {
+		if i.rendering[m] {
+			return "{...}"
+		}
+		i.rendering[m] = true
+		defer delete(i.rendering, m)
+
+		parts := make([]string, len(m.Keys))
+		for index, key := range m.Keys {
+			parts[index] = i.stringifyElement(key) + ": " + i.stringifyElement(m.Entries[key])
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+
+	if text, ok := i.instanceString(value); ok {
+		return text
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// instanceString converts value with its toString method if it is an
+// instance whose class defines one. While an instance's toString runs,
+// converting the same instance again falls back to the default text rather
+// than recursing forever. toString must return a string, so instances can't
+// hand the conversion on to each other endlessly.
+func (i *Interpreter) instanceString(value interface{}) (string, bool) {
+	instance, ok := value.(*object.LoxInstance)
+	if !ok || i.converting[instance] {
+		return "", false
+	}
+	method, ok := instance.Klass.FindMethod("toString")
+	if !ok {
+		return "", false
+	}
+
+	name := method.Declaration.Name
+	if method.Arity() != 0 {
+		panic(loxError.NewRuntimeError(name, name.Lexeme, "toString must take no parameters."))
+	}
+
+	i.converting[instance] = true
+	defer delete(i.converting, instance)
+
+	i.enterCall(name)
+	result := method.Bind(instance).Call(i, nil)
+	i.exitCall()
+
+	text, ok := result.(string)
+	if !ok {
+		panic(loxError.NewRuntimeError(name, name.Lexeme, "toString must return a string."))
+	}
+	return text, true
+}
+
+// stringifyElement renders a value nested in a collection, quoting strings.
+func (i *Interpreter) stringifyElement(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return fmt.Sprintf("%q", str)
+	}
+	return i.Stringify(value)
 }
@@ -0,0 +1,166 @@
+package interpreter
+
+import (
+	"context"
+	"time"
+
+	"github.com/drewslam/goloxTreeInterpreter/ast"
+	"github.com/drewslam/goloxTreeInterpreter/loxError"
+	"github.com/drewslam/goloxTreeInterpreter/token"
+)
+
+// How many steps pass between checks of the context, which are more
+// expensive than the counters.
+const contextCheckInterval = 256
+
+// Limits bounds a single run of Interpret, Evaluate or Call. Zero values
+// mean unlimited.
+type Limits struct {
+	// MaxSteps is the number of statements and expressions that may execute.
+	MaxSteps int
+	// MaxCallDepth is how deeply calls may nest.
+	MaxCallDepth int
+	// Timeout is the wall-clock time a run may take.
+	Timeout time.Duration
+}
+
+// SetContext sets a context whose cancellation stops the running program.
+func (i *Interpreter) SetContext(ctx context.Context) {
+	i.ctx = ctx
+}
+
+// SetLimits sets the limits applied to each run.
+func (i *Interpreter) SetLimits(limits Limits) {
+	i.limits = limits
+}
+
+// beginRun resets the counters and arms the timeout when host code enters
+// the interpreter. Reentrant calls from natives share the outer run.
+func (i *Interpreter) beginRun() {
+	i.running++
+	if i.running > 1 {
+		return
+	}
+
+	i.steps = 0
+	i.callDepth = 0
+	i.at = token.Token{}
+	i.runCtx = i.ctx
+	if i.limits.Timeout > 0 {
+		i.runCtx, i.cancelRun = context.WithTimeout(i.ctx, i.limits.Timeout)
+	}
+}
+
+func (i *Interpreter) endRun() {
+	i.running--
+	if i.running > 0 {
+		return
+	}
+
+	if i.cancelRun != nil {
+		i.cancelRun()
+		i.cancelRun = nil
+	}
+}
+
+// step counts one statement or expression and stops the program once the
+// step budget is spent or the context is done. The error points at the
+// last statement that started running.
+func (i *Interpreter) step() {
+	i.steps++
+	if i.limits.MaxSteps > 0 && i.steps > i.limits.MaxSteps {
+		panic(loxError.NewLimitError(i.at, loxError.ErrStepLimit))
+	}
+	if i.steps%contextCheckInterval == 0 && i.runCtx != nil {
+		if err := i.runCtx.Err(); err != nil {
+			panic(loxError.NewLimitError(i.at, err))
+		}
+	}
+}
+
+// statementToken returns a token locating stmt, if it has one. Blocks and
+// try statements don't, so the statement before them stays the location.
+func statementToken(stmt ast.Stmt) (token.Token, bool) {
+	switch s := stmt.(type) {
+	case *ast.Break:
+		return s.Keyword, true
+	case *ast.Class:
+		return s.Name, true
+	case *ast.Continue:
+		return s.Keyword, true
+	case *ast.Expression:
+		return expressionToken(s.Expr)
+	case *ast.Function:
+		return s.Name, true
+	case *ast.If:
+		return expressionToken(s.Condition)
+	case *ast.Import:
+		return s.Keyword, true
+	case *ast.Interface:
+		return s.Name, true
+	case *ast.Print:
+		return expressionToken(s.Expr)
+	case *ast.Return:
+		return s.Keyword, true
+	case *ast.Throw:
+		return s.Keyword, true
+	case *ast.Trait:
+		return s.Name, true
+	case *ast.Var:
+		return s.Name, true
+	case *ast.While:
+		return s.Keyword, true
+	}
+	return token.Token{}, false
+}
+
+// expressionToken returns a token locating expr, if it has one.
+func expressionToken(expr ast.Expr) (token.Token, bool) {
+	switch e := expr.(type) {
+	case *ast.Assign:
+		return e.Name, true
+	case *ast.Binary:
+		return e.Operator, true
+	case *ast.Call:
+		return e.Paren, true
+	case *ast.Get:
+		return e.Name, true
+	case *ast.Grouping:
+		return expressionToken(e.Expression)
+	case *ast.Index:
+		return e.Bracket, true
+	case *ast.IndexSet:
+		return e.Bracket, true
+	case *ast.Lambda:
+		return e.Keyword, true
+	case *ast.List:
+		return e.Bracket, true
+	case *ast.Logical:
+		return e.Operator, true
+	case *ast.Map:
+		return e.Brace, true
+	case *ast.Set:
+		return e.Name, true
+	case *ast.Super:
+		return e.Keyword, true
+	case *ast.This:
+		return e.Keyword, true
+	case *ast.Unary:
+		return e.Operator, true
+	case *ast.Variable:
+		return e.Name, true
+	}
+	return token.Token{}, false
+}
+
+// enterCall tracks call depth for the call at paren.
+func (i *Interpreter) enterCall(paren token.Token) {
+	i.callDepth++
+	if i.limits.MaxCallDepth > 0 && i.callDepth > i.limits.MaxCallDepth {
+		panic(loxError.NewLimitError(paren, loxError.ErrCallDepthLimit))
+	}
+}
+
+func (i *Interpreter) exitCall() {
+	i.callDepth--
+}
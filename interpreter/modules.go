@@ -0,0 +1,113 @@
+package interpreter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/drewslam/goloxTreeInterpreter/ast"
+	"github.com/drewslam/goloxTreeInterpreter/environment"
+	"github.com/drewslam/goloxTreeInterpreter/loxError"
+	"github.com/drewslam/goloxTreeInterpreter/object"
+	"github.com/drewslam/goloxTreeInterpreter/parser"
+	"github.com/drewslam/goloxTreeInterpreter/resolver"
+	"github.com/drewslam/goloxTreeInterpreter/scanner"
+)
+
+// Compile scans, parses and resolves source for this interpreter, returning
+// the first error.
+func (i *Interpreter) Compile(source string) ([]ast.Stmt, *loxError.LoxError) {
+	scanner := scanner.NewScanner(source)
+	tokens, err := scanner.ScanTokens()
+	if err != nil {
+		return nil, err
+	}
+
+	parser := parser.NewParser(tokens)
+	statements, err := parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := resolver.NewResolver(i)
+	if err := resolver.Resolve(statements); err != nil {
+		return nil, err
+	}
+	return statements, nil
+}
+
+// SetScriptPath records the file the main program was read from, so its
+// imports are found relative to it. Without it they are relative to the
+// working directory.
+func (i *Interpreter) SetScriptPath(path string) {
+	i.moduleDirs[i.Globals] = filepath.Dir(path)
+}
+
+// importModule loads the module named by stmt, or returns it from the cache
+// if it was loaded before. Paths are relative to the directory of the file
+// containing the import, or to the FileAccess root for source that didn't
+// come from a file. Modules are read under the same FileAccess as the
+// file natives, so a sandboxed interpreter can't import from disk.
+func (i *Interpreter) importModule(stmt *ast.Import) *object.LoxModule {
+	name, _ := stmt.Path.Literal.(string)
+	path := name
+	if dir, ok := i.moduleDirs[i.environment.GlobalScope()]; ok && !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	// Without an importing file, relative paths are left to checkPath,
+	// which takes them relative to the FileAccess root if there is one.
+	path, err := i.checkPath(path)
+	if err != nil {
+		panic(loxError.NewRuntimeError(stmt.Path, stmt.Path.Lexeme, fmt.Sprintf("Can't import module '%s': %v", name, err)))
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		panic(loxError.NewRuntimeError(stmt.Path, stmt.Path.Lexeme, fmt.Sprintf("Can't resolve module path: %v.", err)))
+	}
+
+	if module, exists := i.modules[path]; exists {
+		return module
+	}
+	if i.loading[path] {
+		panic(loxError.NewRuntimeError(stmt.Path, stmt.Path.Lexeme, "Circular import of module '"+name+"'."))
+	}
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		panic(loxError.NewRuntimeError(stmt.Path, stmt.Path.Lexeme, fmt.Sprintf("Can't read module '%s': %v.", name, err)))
+	}
+	statements, lerr := i.Compile(string(source))
+	if lerr != nil {
+		panic(loxError.NewRuntimeError(stmt.Path, stmt.Path.Lexeme, fmt.Sprintf("Error in module '%s': %v", name, lerr)))
+	}
+
+	// Each module runs in its own global environment, which sees the
+	// natives but not the importer's globals.
+	env := environment.NewGlobalEnvironment(i.builtins)
+	i.moduleDirs[env] = filepath.Dir(path)
+
+	i.loading[path] = true
+	defer delete(i.loading, path)
+	i.ExecuteBlock(statements, env)
+
+	module := object.NewLoxModule(name, env)
+	i.modules[path] = module
+	return module
+}
+
+func (i *Interpreter) VisitImportStmt(stmt *ast.Import) interface{} {
+	module := i.importModule(stmt)
+
+	if len(stmt.Names) == 0 {
+		i.environment.Define(stmt.Alias.Lexeme, module)
+		return nil
+	}
+	for _, name := range stmt.Names {
+		value, exists := module.Member(name.Lexeme)
+		if !exists {
+			panic(loxError.NewRuntimeError(name, name.Lexeme, "Module '"+module.Name+"' has no member '"+name.Lexeme+"'."))
+		}
+		i.environment.Define(name.Lexeme, value)
+	}
+	return nil
+}
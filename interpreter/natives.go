@@ -0,0 +1,120 @@
+package interpreter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/drewslam/goloxTreeInterpreter/environment"
+	"github.com/drewslam/goloxTreeInterpreter/loxCallable"
+	"github.com/drewslam/goloxTreeInterpreter/object"
+)
+
+// registerNatives defines the natives that need the interpreter's own
+// state, such as its input and output streams, or the object types.
+func (i *Interpreter) registerNatives(env *environment.Environment) {
+	// readLine returns the next line of input without its line ending,
+	// or nil at end of input.
+	loxCallable.DefineReflected(env, "readLine", func() (any, error) {
+		return i.readLine()
+	})
+	// type returns the name of the kind of value x holds.
+	loxCallable.DefineReflected(env, "type", func(x any) string {
+		return typeName(x)
+	})
+	loxCallable.DefineReflected(env, "classOf", func(x any) (any, error) {
+		instance, ok := x.(*object.LoxInstance)
+		if !ok {
+			return nil, fmt.Errorf("classOf expects an instance but got a %s.", typeName(x))
+		}
+		return instance.Klass, nil
+	})
+	// str returns x as print shows it.
+	loxCallable.DefineReflected(env, "str", func(x any) string {
+		return i.Stringify(x)
+	})
+	// num parses a string as a number; numbers are returned unchanged.
+	loxCallable.DefineReflected(env, "num", toNumber)
+	// int is num truncated toward zero.
+	loxCallable.DefineReflected(env, "int", func(x any) (float64, error) {
+		num, err := toNumber(x)
+		return math.Trunc(num), err
+	})
+	// format and printf take the template language documented on Format.
+	loxCallable.DefineReflected(env, "format", func(template string, arguments ...any) (string, error) {
+		return i.Format(template, arguments)
+	})
+	loxCallable.DefineReflected(env, "printf", func(template string, arguments ...any) error {
+		text, err := i.Format(template, arguments)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(i.stdout, text)
+		return nil
+	})
+	// input writes prompt and then reads a line like readLine.
+	loxCallable.DefineReflected(env, "input", func(prompt any) (any, error) {
+		fmt.Fprint(i.stdout, i.Stringify(prompt))
+		return i.readLine()
+	})
+}
+
+func typeName(x interface{}) string {
+	switch x.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case *object.LoxClass:
+		return "class"
+	case *object.LoxInstance:
+		return "instance"
+	case *object.LoxList:
+		return "list"
+	case *object.LoxMap:
+		return "map"
+	case *object.LoxModule:
+		return "module"
+	case *object.LoxTrait:
+		return "trait"
+	case *object.LoxInterface:
+		return "interface"
+	case loxCallable.LoxCallable:
+		return "function"
+	}
+	return "unknown"
+}
+
+func toNumber(x interface{}) (float64, error) {
+	switch v := x.(type) {
+	case float64:
+		return v, nil
+	case string:
+		num, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("Can't convert %q to a number.", v)
+		}
+		return num, nil
+	}
+	return 0, fmt.Errorf("Can't convert a %s to a number.", typeName(x))
+}
+
+// readLine reads a line from the interpreter's shared input reader.
+func (i *Interpreter) readLine() (any, error) {
+	line, err := i.stdin.ReadString('\n')
+	if err == io.EOF {
+		if line == "" {
+			return nil, nil
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("Can't read input: %v.", err)
+	}
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), nil
+}
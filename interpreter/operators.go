@@ -0,0 +1,68 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/drewslam/goloxTreeInterpreter/loxError"
+	"github.com/drewslam/goloxTreeInterpreter/object"
+	"github.com/drewslam/goloxTreeInterpreter/token"
+)
+
+// operatorMethods maps binary operators to the methods that overload them
+// when the left operand is an instance. != is the negation of __eq__.
+var operatorMethods = map[token.TokenType]string{
+	token.PLUS:          "__add__",
+	token.MINUS:         "__sub__",
+	token.STAR:          "__mul__",
+	token.SLASH:         "__div__",
+	token.LESS:          "__lt__",
+	token.LESS_EQUAL:    "__le__",
+	token.GREATER:       "__gt__",
+	token.GREATER_EQUAL: "__ge__",
+	token.EQUAL_EQUAL:   "__eq__",
+	token.BANG_EQUAL:    "__eq__",
+}
+
+// callOperator calls the method name on operand if it is an instance whose
+// class defines it, reporting whether it did.
+func (i *Interpreter) callOperator(operator token.Token, operand interface{}, name string, arguments ...interface{}) (interface{}, bool) {
+	instance, ok := operand.(*object.LoxInstance)
+	if !ok {
+		return nil, false
+	}
+	method, ok := instance.Klass.FindMethod(name)
+	if !ok {
+		return nil, false
+	}
+
+	if method.Arity() != len(arguments) {
+		message := fmt.Sprintf("Operator method '%s' must take %d parameters but takes %d.", name, len(arguments), method.Arity())
+		panic(loxError.NewRuntimeError(operator, operator.Lexeme, message))
+	}
+
+	i.enterCall(operator)
+	result := method.Bind(instance).Call(i, arguments)
+	i.exitCall()
+	return result, true
+}
+
+// binaryOperator applies an overloaded binary operator, reporting whether
+// the left operand overloads it.
+func (i *Interpreter) binaryOperator(operator token.Token, left interface{}, right interface{}) (interface{}, bool) {
+	name, ok := operatorMethods[operator.Type]
+	if !ok {
+		return nil, false
+	}
+
+	result, ok := i.callOperator(operator, left, name, right)
+	if !ok {
+		return nil, false
+	}
+	switch operator.Type {
+	case token.EQUAL_EQUAL:
+		return i.isTruthy(result), true
+	case token.BANG_EQUAL:
+		return !i.isTruthy(result), true
+	}
+	return result, true
+}
@@ -3,9 +3,12 @@ package loxCallable
 import (
 	"github.com/drewslam/goloxTreeInterpreter/ast"
 	"github.com/drewslam/goloxTreeInterpreter/environment"
+	"github.com/drewslam/goloxTreeInterpreter/token"
 )
 
 type Interpreter interface {
+	// CallGetter runs a getter read through name, counting it as a call.
+	CallGetter(getter LoxCallable, name token.Token) interface{}
 	ExecuteBlock(statements []ast.Stmt, environment *environment.Environment) interface{}
 	GetGlobals() *environment.Environment
 }
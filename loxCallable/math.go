@@ -0,0 +1,55 @@
+package loxCallable
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/drewslam/goloxTreeInterpreter/environment"
+)
+
+// RegisterMath defines the members of the math namespace in env. The random
+// functions draw from random, which math.seed(n) reseeds so runs can be
+// reproduced.
+func RegisterMath(env *environment.Environment, random *rand.Rand) {
+	env.Define("pi", math.Pi)
+	env.Define("inf", math.Inf(1))
+	env.Define("nan", math.NaN())
+
+	DefineReflected(env, "sqrt", math.Sqrt)
+	DefineReflected(env, "pow", math.Pow)
+	DefineReflected(env, "abs", math.Abs)
+	DefineReflected(env, "floor", math.Floor)
+	DefineReflected(env, "ceil", math.Ceil)
+	DefineReflected(env, "round", math.Round)
+	DefineReflected(env, "sin", math.Sin)
+	DefineReflected(env, "cos", math.Cos)
+	DefineReflected(env, "tan", math.Tan)
+	DefineReflected(env, "log", math.Log)
+	DefineReflected(env, "exp", math.Exp)
+	DefineReflected(env, "isNaN", math.IsNaN)
+	DefineReflected(env, "min", func(first float64, rest ...float64) float64 {
+		for _, x := range rest {
+			first = math.Min(first, x)
+		}
+		return first
+	})
+	DefineReflected(env, "max", func(first float64, rest ...float64) float64 {
+		for _, x := range rest {
+			first = math.Max(first, x)
+		}
+		return first
+	})
+	// random returns a number in [0, 1).
+	DefineReflected(env, "random", random.Float64)
+	// randomInt returns an integer in [min, max], both inclusive.
+	DefineReflected(env, "randomInt", func(min int64, max int64) (int64, error) {
+		if max < min {
+			return 0, fmt.Errorf("randomInt max %d is less than min %d.", max, min)
+		}
+		return min + random.Int63n(max-min+1), nil
+	})
+	DefineReflected(env, "seed", func(seed int64) {
+		random.Seed(seed)
+	})
+}
@@ -0,0 +1,241 @@
+package loxCallable
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/drewslam/goloxTreeInterpreter/environment"
+	"github.com/drewslam/goloxTreeInterpreter/loxError"
+	"github.com/drewslam/goloxTreeInterpreter/token"
+)
+
+// NativeFunction is a LoxCallable implemented in Go. Variadic natives accept
+// Arity or more arguments.
+type NativeFunction struct {
+	Name     string
+	Params   int
+	Variadic bool
+	Function func(interpreter Interpreter, arguments []interface{}) (interface{}, error)
+}
+
+func NewNativeFunction(name string, arity int, function func(Interpreter, []interface{}) (interface{}, error)) *NativeFunction {
+	return &NativeFunction{
+		Name:     name,
+		Params:   arity,
+		Function: function,
+	}
+}
+
+// NewMethod returns a native that doesn't use the interpreter, such as a
+// method of a list, map or string closed over its receiver.
+func NewMethod(name string, arity int, fn func(arguments []interface{}) (interface{}, error)) *NativeFunction {
+	return NewNativeFunction(name, arity, func(interpreter Interpreter, arguments []interface{}) (interface{}, error) {
+		return fn(arguments)
+	})
+}
+
+func (n *NativeFunction) Arity() int {
+	return n.Params
+}
+
+// Invoke runs the native and returns any error it reported, leaving the
+// caller to attach a source location. A Go panic in the native is returned
+// as an error so it can't crash the host; Lox errors raised by callbacks
+// into the interpreter are passed through unchanged.
+func (n *NativeFunction) Invoke(interpreter Interpreter, arguments []interface{}) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(*loxError.LoxError); ok {
+				panic(r)
+			}
+			result = nil
+			err = fmt.Errorf("Native '%s' failed: %v.", n.Name, r)
+		}
+	}()
+
+	return n.Function(interpreter, arguments)
+}
+
+func (n *NativeFunction) Call(interpreter Interpreter, arguments []interface{}) interface{} {
+	result, err := n.Invoke(interpreter, arguments)
+	if err != nil {
+		panic(loxError.NewRuntimeError(token.Token{}, n.Name, err.Error()))
+	}
+	return result
+}
+
+func (n *NativeFunction) String() string {
+	return "<native fn>"
+}
+
+var _ LoxCallable = (*NativeFunction)(nil)
+
+// CheckArity reports whether count arguments may be passed to callable.
+func CheckArity(callable LoxCallable, count int) bool {
+	if native, ok := callable.(*NativeFunction); ok && native.Variadic {
+		return count >= native.Params
+	}
+	return count == callable.Arity()
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// NewReflectedNative adapts an arbitrary Go function to a NativeFunction.
+// Parameters and results may be numbers, strings, bools or interface{}
+// (which receives the raw Lox value, including nil), and the last parameter
+// may be variadic. A trailing error result becomes a Lox runtime error.
+func NewReflectedNative(name string, fn any) (*NativeFunction, error) {
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Func {
+		return nil, fmt.Errorf("native '%s' must be a function, got %T", name, fn)
+	}
+	fnType := value.Type()
+
+	for i := 0; i < fnType.NumIn(); i++ {
+		paramType := fnType.In(i)
+		if fnType.IsVariadic() && i == fnType.NumIn()-1 {
+			paramType = paramType.Elem()
+		}
+		if !isConvertible(paramType) {
+			return nil, fmt.Errorf("native '%s': unsupported parameter type %s", name, paramType)
+		}
+	}
+
+	returnsError := false
+	switch fnType.NumOut() {
+	case 0:
+	case 1:
+		returnsError = fnType.Out(0) == errorType
+		if !returnsError && !isConvertible(fnType.Out(0)) {
+			return nil, fmt.Errorf("native '%s': unsupported result type %s", name, fnType.Out(0))
+		}
+	case 2:
+		if !isConvertible(fnType.Out(0)) || fnType.Out(1) != errorType {
+			return nil, fmt.Errorf("native '%s': two results must be (value, error)", name)
+		}
+		returnsError = true
+	default:
+		return nil, fmt.Errorf("native '%s': too many results", name)
+	}
+
+	arity := fnType.NumIn()
+	if fnType.IsVariadic() {
+		arity--
+	}
+
+	native := NewNativeFunction(name, arity, func(interpreter Interpreter, arguments []interface{}) (interface{}, error) {
+		in := make([]reflect.Value, len(arguments))
+		for i, argument := range arguments {
+			paramType := variadicParam(fnType, i)
+			converted, err := fromLox(argument, paramType)
+			if err != nil {
+				return nil, fmt.Errorf("Argument %d to '%s' %v.", i+1, name, err)
+			}
+			in[i] = converted
+		}
+
+		out := value.Call(in)
+
+		if returnsError {
+			if err, _ := out[len(out)-1].Interface().(error); err != nil {
+				return nil, err
+			}
+			out = out[:len(out)-1]
+		}
+		if len(out) == 0 {
+			return nil, nil
+		}
+		return toLox(out[0]), nil
+	})
+	native.Variadic = fnType.IsVariadic()
+	return native, nil
+}
+
+// DefineReflected defines name in env as fn adapted by NewReflectedNative.
+// It is for natives built into the interpreter, so an unsupported
+// signature is a bug and panics.
+func DefineReflected(env *environment.Environment, name string, fn any) {
+	native, err := NewReflectedNative(name, fn)
+	if err != nil {
+		panic(err)
+	}
+	env.Define(name, native)
+}
+
+// variadicParam returns the type of the i-th argument, expanding the
+// variadic parameter if there is one.
+func variadicParam(fnType reflect.Type, i int) reflect.Type {
+	if fnType.IsVariadic() && i >= fnType.NumIn()-1 {
+		return fnType.In(fnType.NumIn() - 1).Elem()
+	}
+	return fnType.In(i)
+}
+
+func isConvertible(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.String, reflect.Bool:
+		return true
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	}
+	return false
+}
+
+// fromLox converts a Lox value to the Go parameter type t.
+func fromLox(argument interface{}, t reflect.Type) (reflect.Value, error) {
+	if t.Kind() == reflect.Interface {
+		if argument == nil {
+			return reflect.Zero(t), nil
+		}
+		return reflect.ValueOf(argument), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if num, ok := argument.(float64); ok {
+			return reflect.ValueOf(num).Convert(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("must be a number")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if num, ok := argument.(float64); ok && num == float64(int64(num)) {
+			return reflect.ValueOf(int64(num)).Convert(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("must be an integer")
+	case reflect.String:
+		if str, ok := argument.(string); ok {
+			return reflect.ValueOf(str).Convert(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("must be a string")
+	case reflect.Bool:
+		if b, ok := argument.(bool); ok {
+			return reflect.ValueOf(b).Convert(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("must be a boolean")
+	}
+	return reflect.Value{}, fmt.Errorf("has unsupported type %s", t)
+}
+
+// toLox converts a Go result to a Lox value.
+func toLox(result reflect.Value) interface{} {
+	switch result.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return result.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(result.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(result.Uint())
+	case reflect.String:
+		return result.String()
+	case reflect.Bool:
+		return result.Bool()
+	case reflect.Interface:
+		if result.IsNil() {
+			return nil
+		}
+		// Convert the dynamic value the same way as a typed result.
+		return toLox(result.Elem())
+	}
+	return result.Interface()
+}
@@ -1,18 +1,30 @@
 package loxError
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/drewslam/goloxTreeInterpreter/token"
 )
 
+// Causes of limit errors, matched with errors.Is.
+var (
+	ErrStepLimit      = errors.New("step limit exceeded")
+	ErrCallDepthLimit = errors.New("call depth limit exceeded")
+)
+
 // LoxError represents an error in the interpreter
 type LoxError struct {
 	Line    int
 	Where   string
 	Message string
 	IsFatal bool
+	Cause   error
+	// Thrown is set for errors raised by a throw statement, which carry the
+	// thrown Lox value in Value.
+	Thrown bool
+	Value  interface{}
 }
 
 // Error implements the error interface for RuntimeError.
@@ -20,6 +32,17 @@ func (e *LoxError) Error() string {
 	return fmt.Sprintf("[line %d] Error at %s: %s", e.Line, e.Where, e.Message)
 }
 
+// Unwrap returns the cause of a limit error, or nil.
+func (e *LoxError) Unwrap() error {
+	return e.Cause
+}
+
+// IsLimit reports whether the error was raised because an execution limit
+// or the interpreter's context stopped the program.
+func (e *LoxError) IsLimit() bool {
+	return e.Cause != nil
+}
+
 // NewParseError creates a parse error (non-fatal)
 func NewParseError(token token.Token, message string) *LoxError {
 	where := "end"
@@ -44,6 +67,31 @@ func NewRuntimeError(token token.Token, where string, message string) *LoxError
 	}
 }
 
+// NewLimitError creates a runtime error (fatal) for an exhausted limit or a
+// cancelled context. cause is one of the Err* values or the context's error.
+func NewLimitError(token token.Token, cause error) *LoxError {
+	return &LoxError{
+		Line:    token.Line,
+		Where:   token.Lexeme,
+		Message: "Execution stopped: " + cause.Error() + ".",
+		IsFatal: true,
+		Cause:   cause,
+	}
+}
+
+// NewThrowError creates a runtime error (fatal) for a value thrown by a
+// throw statement and not yet caught.
+func NewThrowError(token token.Token, value interface{}, message string) *LoxError {
+	return &LoxError{
+		Line:    token.Line,
+		Where:   token.Lexeme,
+		Message: message,
+		IsFatal: true,
+		Thrown:  true,
+		Value:   value,
+	}
+}
+
 // NewScanError creates a scan error (non-fatal)
 func NewScanError(line int, message string) *LoxError {
 	return &LoxError{
@@ -54,25 +102,16 @@ func NewScanError(line int, message string) *LoxError {
 	}
 }
 
-// Report error prints an error without panicking
-func ReportError(err *LoxError) {
-	fmt.Println(err.Error())
-}
-
-// ReportAndPanic reports an error and panics if it's fatal
-func ReportAndPanic(err *LoxError) {
-	fmt.Println(err.Error())
-	panic(err)
+// ReportError writes an error to w without panicking
+func ReportError(w io.Writer, err *LoxError) {
+	fmt.Fprintln(w, err.Error())
 }
 
-func HandleRecoveredError(r any) {
+// HandleRecoveredError converts a recovered panic value back into a LoxError
+// so it can be returned to the caller instead of ending the process.
+func HandleRecoveredError(r any) *LoxError {
 	if err, ok := r.(*LoxError); ok {
-		fmt.Println(err.Error())
-		if err.IsFatal {
-			os.Exit(1)
-		}
-		// Error is non-fatal
-		return
+		return err
 	}
 
 	// Not a LoxError -- Repanic
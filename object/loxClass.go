@@ -1,15 +1,31 @@
 package object
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/drewslam/goloxTreeInterpreter/ast"
 	"github.com/drewslam/goloxTreeInterpreter/loxCallable"
+	"github.com/drewslam/goloxTreeInterpreter/loxError"
+	"github.com/drewslam/goloxTreeInterpreter/token"
 )
 
 // type Interpreter interface{}
 
+// LoxClass is a class. Private methods are kept in Methods under their '#'
+// names, but are only looked up through the declaring class.
 type LoxClass struct {
-	Name       string
-	Superclass *LoxClass
-	Methods    map[string]*LoxFunction
+	Name         string
+	Superclass   *LoxClass
+	Methods      map[string]*LoxFunction
+	ClassMethods map[string]*LoxFunction
+	Traits       []*LoxTrait
+	Interfaces   []*LoxInterface
+	Declaration  *ast.Class
+	// Abstract holds the names AbstractMethods returned when the class was
+	// declared, so instantiating it doesn't walk the hierarchy again.
+	Abstract []string
 }
 
 func (l *LoxClass) FindMethod(name string) (*LoxFunction, bool) {
@@ -24,11 +40,108 @@ func (l *LoxClass) FindMethod(name string) (*LoxFunction, bool) {
 	return nil, false
 }
 
+// FindClassMethod looks up a class method, which subclasses inherit.
+func (l *LoxClass) FindClassMethod(name string) (*LoxFunction, bool) {
+	if value, ok := l.ClassMethods[name]; ok {
+		return value, true
+	}
+
+	if l.Superclass != nil {
+		return l.Superclass.FindClassMethod(name)
+	}
+
+	return nil, false
+}
+
+// Get returns the named class method, or runs it if it is a getter.
+func (l *LoxClass) Get(interpreter loxCallable.Interpreter, name token.Token) interface{} {
+	if method, exists := l.FindClassMethod(name.Lexeme); exists {
+		if method.Declaration.IsGetter {
+			return interpreter.CallGetter(method, name)
+		}
+		return method
+	}
+
+	panic(loxError.NewRuntimeError(name, name.Lexeme, "Undefined class method '"+name.Lexeme+"'."))
+}
+
+// IsSubclassOf reports whether l is other or inherits from it.
+func (l *LoxClass) IsSubclassOf(other *LoxClass) bool {
+	for class := l; class != nil; class = class.Superclass {
+		if class == other {
+			return true
+		}
+	}
+	return false
+}
+
+// Includes reports whether l or one of its superclasses uses trait.
+func (l *LoxClass) Includes(trait *LoxTrait) bool {
+	for class := l; class != nil; class = class.Superclass {
+		for _, t := range class.Traits {
+			if t == trait {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Implements reports whether l or one of its superclasses claims to
+// implement iface.
+func (l *LoxClass) Implements(iface *LoxInterface) bool {
+	for class := l; class != nil; class = class.Superclass {
+		for _, i := range class.Interfaces {
+			if i == iface {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AbstractMethods returns the sorted names of the abstract methods that
+// neither l nor its superclasses implement.
+func (l *LoxClass) AbstractMethods() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for class := l; class != nil; class = class.Superclass {
+		for name := range class.Methods {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if method, _ := l.FindMethod(name); method.Declaration.IsAbstract {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckInstantiable returns an error if l has unimplemented abstract
+// methods.
+func (l *LoxClass) CheckInstantiable() error {
+	if len(l.Abstract) == 0 {
+		return nil
+	}
+	return fmt.Errorf("Can't instantiate abstract class %s; missing %s.", l.Name, "'"+strings.Join(l.Abstract, "', '")+"'")
+}
+
 func (l *LoxClass) String() string {
 	return l.Name
 }
 
 func (l *LoxClass) Call(interpreter loxCallable.Interpreter, arguments []interface{}) interface{} {
+	if err := l.CheckInstantiable(); err != nil {
+		var at token.Token
+		if l.Declaration != nil {
+			at = l.Declaration.Name
+		}
+		panic(loxError.NewRuntimeError(at, l.Name, err.Error()))
+	}
+
 	instance := &LoxInstance{
 		Klass:  l,
 		Fields: make(map[string]interface{}),
@@ -4,12 +4,11 @@ import (
 	"fmt"
 
 	"github.com/drewslam/goloxTreeInterpreter/ast"
+	"github.com/drewslam/goloxTreeInterpreter/completion"
 	"github.com/drewslam/goloxTreeInterpreter/environment"
 	"github.com/drewslam/goloxTreeInterpreter/loxCallable"
 	"github.com/drewslam/goloxTreeInterpreter/loxDebug"
 	"github.com/drewslam/goloxTreeInterpreter/loxError"
-	"github.com/drewslam/goloxTreeInterpreter/returnValue"
-	"github.com/drewslam/goloxTreeInterpreter/token"
 )
 
 type LoxFunction struct {
@@ -37,6 +36,9 @@ func (l *LoxFunction) Bind(instance *LoxInstance) *LoxFunction {
 }
 
 func (l *LoxFunction) String() string {
+	if l.Declaration.Name.Lexeme == "" {
+		return "<fn anonymous>"
+	}
 	return fmt.Sprintf("<fn %v>", l.Declaration.Name.Lexeme)
 }
 
@@ -49,7 +51,12 @@ func (l *LoxFunction) Call(interpreter loxCallable.Interpreter, arguments []inte
 	if len(arguments) != len(l.Declaration.Params) {
 		message := (fmt.Sprintf("Expected %d arguments but got %d.", len(l.Declaration.Params), len(arguments)))
 		err := loxError.NewRuntimeError(l.Declaration.Name, "", message)
-		loxError.ReportAndPanic(err)
+		panic(err)
+	}
+
+	if l.Declaration.IsAbstract {
+		message := fmt.Sprintf("Abstract method '%s' has no implementation.", l.Declaration.Name.Lexeme)
+		panic(loxError.NewRuntimeError(l.Declaration.Name, l.Declaration.Name.Lexeme, message))
 	}
 
 	env := environment.NewEnvironment(l.Closure)
@@ -59,41 +66,22 @@ func (l *LoxFunction) Call(interpreter loxCallable.Interpreter, arguments []inte
 		env.Define(param.Lexeme, arguments[i])
 	}
 
-	for _, param := range l.Declaration.Params {
-		val, err := env.Get(token.Token{Lexeme: param.Lexeme})
-		loxDebug.LogDebug("Parameter %s = %v (error %v)\n", param.Lexeme, val, err)
-	}
-
 	var result interface{} = nil
 
-	// Try executing the function
-	func() {
-		defer func() {
-			if r := recover(); r != nil {
-				if rv, ok := r.(*returnValue.ReturnValue); ok {
-					loxDebug.LogDebug("Recovered return value:", rv.Value)
-					result = rv.Value
-					loxDebug.LogDebug("Stored returnVal in Call():", result)
-					// return
-				} else {
-					loxDebug.LogError("Recovered unknown panic:", r)
-					panic(r) // Re-panic other errors
-				}
-			}
-		}()
-
-		loxDebug.LogDebug("Executing function body for:", l.String())
-		interpreter.ExecuteBlock(l.Declaration.Body, env)
-	}()
+	loxDebug.LogDebug("Executing function body for: %s", l.String())
+	completed := interpreter.ExecuteBlock(l.Declaration.Body, env)
+	if c, ok := completed.(*completion.Completion); ok && c.Type == completion.RETURN {
+		result = c.Value
+	}
 
 	// Ensure constructors return 'this'
 	if l.IsInitializer {
-		val, _ := l.Closure.GetAt(0, "this")
+		val := l.Closure.GetAt(0, 0)
 		loxDebug.LogDebug("Returning 'this' from Call()")
 		return val
 	}
 
-	loxDebug.LogDebug("Returning from Call():", result)
+	loxDebug.LogDebug("Returning from Call(): %v", result)
 	return result
 }
 
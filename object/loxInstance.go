@@ -3,30 +3,77 @@ package object
 import (
 	"fmt"
 
+	"github.com/drewslam/goloxTreeInterpreter/ast"
+	"github.com/drewslam/goloxTreeInterpreter/loxCallable"
 	"github.com/drewslam/goloxTreeInterpreter/loxError"
 	"github.com/drewslam/goloxTreeInterpreter/token"
 )
 
+// LoxInstance is an instance of a class. Private fields are kept apart
+// from Fields, per declaring class, so a subclass can't see or clobber the
+// private fields of its superclasses.
 type LoxInstance struct {
-	Klass  *LoxClass
-	Fields map[string]interface{}
+	Klass   *LoxClass
+	Fields  map[string]interface{}
+	Private map[*ast.Class]map[string]interface{}
 }
 
 func (l *LoxInstance) String() string {
 	return l.Klass.Name + " instance"
 }
 
-func (l *LoxInstance) Get(name token.Token) interface{} {
+// Get returns a field, or a method bound to the instance. Getters are run
+// and their result returned.
+func (l *LoxInstance) Get(interpreter loxCallable.Interpreter, name token.Token) interface{} {
 	if value, exists := l.Fields[name.Lexeme]; exists {
 		return value
 	}
 
 	method, exists := l.Klass.FindMethod(name.Lexeme)
 	if exists {
+		if method.Declaration.IsGetter {
+			return interpreter.CallGetter(method.Bind(l), name)
+		}
 		return method.Bind(l)
 	}
 
-	return loxError.NewRuntimeError(name, fmt.Sprintf("[Line %d]", name.Line), "Undefined property '"+name.Lexeme+"'.")
+	panic(loxError.NewRuntimeError(name, fmt.Sprintf("[Line %d]", name.Line), "Undefined property '"+name.Lexeme+"'."))
+}
+
+// GetPrivate returns a private field or method of the class declared by
+// owner.
+func (l *LoxInstance) GetPrivate(interpreter loxCallable.Interpreter, owner *ast.Class, name token.Token) interface{} {
+	if value, exists := l.Private[owner][name.Lexeme]; exists {
+		return value
+	}
+
+	for class := l.Klass; class != nil; class = class.Superclass {
+		if class.Declaration != owner {
+			continue
+		}
+		if method, exists := class.Methods[name.Lexeme]; exists {
+			if method.Declaration.IsGetter {
+				return interpreter.CallGetter(method.Bind(l), name)
+			}
+			return method.Bind(l)
+		}
+		break
+	}
+
+	panic(loxError.NewRuntimeError(name, name.Lexeme, "Undefined private member '"+name.Lexeme+"'."))
+}
+
+// SetPrivate sets a private field of the class declared by owner.
+func (l *LoxInstance) SetPrivate(owner *ast.Class, name token.Token, value interface{}) {
+	if l.Private == nil {
+		l.Private = make(map[*ast.Class]map[string]interface{})
+	}
+	fields, exists := l.Private[owner]
+	if !exists {
+		fields = make(map[string]interface{})
+		l.Private[owner] = fields
+	}
+	fields[name.Lexeme] = value
 }
 
 func (l *LoxInstance) Set(name token.Token, value interface{}) {
@@ -0,0 +1,17 @@
+package object
+
+import (
+	"github.com/drewslam/goloxTreeInterpreter/ast"
+)
+
+// LoxInterface is a named set of method signatures. A class that claims to
+// implement it must define every method, with the same number of
+// parameters, when it is declared.
+type LoxInterface struct {
+	Name    string
+	Methods []*ast.Function
+}
+
+func (i *LoxInterface) String() string {
+	return "<interface " + i.Name + ">"
+}
@@ -0,0 +1,127 @@
+package object
+
+import (
+	"fmt"
+
+	"github.com/drewslam/goloxTreeInterpreter/loxCallable"
+	"github.com/drewslam/goloxTreeInterpreter/loxError"
+	"github.com/drewslam/goloxTreeInterpreter/token"
+)
+
+type LoxList struct {
+	Elements []interface{}
+}
+
+func NewLoxList(elements []interface{}) *LoxList {
+	return &LoxList{
+		Elements: elements,
+	}
+}
+
+// Index converts a Lox value to a position in the list. With allowEnd, the
+// position just past the last element is also accepted.
+func (l *LoxList) Index(value interface{}, allowEnd bool) (int, error) {
+	num, ok := value.(float64)
+	if !ok || num != float64(int(num)) {
+		return 0, fmt.Errorf("List index must be an integer.")
+	}
+
+	index := int(num)
+	limit := len(l.Elements)
+	if allowEnd {
+		limit++
+	}
+	if index < 0 || index >= limit {
+		return 0, fmt.Errorf("List index %d out of range for length %d.", index, len(l.Elements))
+	}
+	return index, nil
+}
+
+func (l *LoxList) GetAt(value interface{}) (interface{}, error) {
+	index, err := l.Index(value, false)
+	if err != nil {
+		return nil, err
+	}
+	return l.Elements[index], nil
+}
+
+func (l *LoxList) SetAt(value interface{}, element interface{}) error {
+	index, err := l.Index(value, false)
+	if err != nil {
+		return err
+	}
+	l.Elements[index] = element
+	return nil
+}
+
+// Get returns the named list method bound to this list.
+func (l *LoxList) Get(name token.Token) interface{} {
+	switch name.Lexeme {
+	case "len":
+		return loxCallable.NewMethod(name.Lexeme, 0, func(arguments []interface{}) (interface{}, error) {
+			return float64(len(l.Elements)), nil
+		})
+	case "push":
+		return loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			l.Elements = append(l.Elements, arguments[0])
+			return nil, nil
+		})
+	case "pop":
+		return loxCallable.NewMethod(name.Lexeme, 0, func(arguments []interface{}) (interface{}, error) {
+			if len(l.Elements) == 0 {
+				return nil, fmt.Errorf("Can't pop from an empty list.")
+			}
+			last := l.Elements[len(l.Elements)-1]
+			l.Elements = l.Elements[:len(l.Elements)-1]
+			return last, nil
+		})
+	case "insert":
+		return loxCallable.NewMethod(name.Lexeme, 2, func(arguments []interface{}) (interface{}, error) {
+			index, err := l.Index(arguments[0], true)
+			if err != nil {
+				return nil, err
+			}
+			l.Elements = append(l.Elements, nil)
+			copy(l.Elements[index+1:], l.Elements[index:])
+			l.Elements[index] = arguments[1]
+			return nil, nil
+		})
+	case "remove":
+		return loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			index, err := l.Index(arguments[0], false)
+			if err != nil {
+				return nil, err
+			}
+			removed := l.Elements[index]
+			l.Elements = append(l.Elements[:index], l.Elements[index+1:]...)
+			return removed, nil
+		})
+	case "slice":
+		// slice(start) or slice(start, end), end exclusive.
+		slice := loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			if len(arguments) > 2 {
+				return nil, fmt.Errorf("Expected 1 or 2 arguments but got %d.", len(arguments))
+			}
+			start, err := l.Index(arguments[0], true)
+			if err != nil {
+				return nil, err
+			}
+			end := len(l.Elements)
+			if len(arguments) == 2 {
+				if end, err = l.Index(arguments[1], true); err != nil {
+					return nil, err
+				}
+			}
+			if end < start {
+				return nil, fmt.Errorf("Slice end %d is before start %d.", end, start)
+			}
+			elements := make([]interface{}, end-start)
+			copy(elements, l.Elements[start:end])
+			return NewLoxList(elements), nil
+		})
+		slice.Variadic = true
+		return slice
+	}
+
+	panic(loxError.NewRuntimeError(name, name.Lexeme, "Undefined list method '"+name.Lexeme+"'."))
+}
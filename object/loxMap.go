@@ -0,0 +1,123 @@
+package object
+
+import (
+	"fmt"
+
+	"github.com/drewslam/goloxTreeInterpreter/loxCallable"
+	"github.com/drewslam/goloxTreeInterpreter/loxError"
+	"github.com/drewslam/goloxTreeInterpreter/token"
+)
+
+// LoxMap is an insertion-ordered map. Keys compare with Go's ==, the same
+// test the interpreter's isEqual uses, so instances are keyed by identity.
+type LoxMap struct {
+	Keys    []interface{}
+	Entries map[interface{}]interface{}
+}
+
+func NewLoxMap() *LoxMap {
+	return &LoxMap{
+		Entries: make(map[interface{}]interface{}),
+	}
+}
+
+// checkKey rejects values that can't be map keys.
+func checkKey(key interface{}) error {
+	switch key.(type) {
+	case nil, bool, float64, string, *LoxInstance:
+		return nil
+	}
+	return fmt.Errorf("Map keys must be strings, numbers, booleans, nil or instances.")
+}
+
+// formatKey renders a key for error messages, quoting strings so they can be
+// told apart from other values.
+func formatKey(key interface{}) string {
+	switch k := key.(type) {
+	case nil:
+		return "nil"
+	case float64:
+		return fmt.Sprintf("%g", k)
+	case string:
+		return fmt.Sprintf("%q", k)
+	}
+	return fmt.Sprintf("%v", key)
+}
+
+func (m *LoxMap) GetKey(key interface{}) (interface{}, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	value, ok := m.Entries[key]
+	if !ok {
+		return nil, fmt.Errorf("Key %s not found.", formatKey(key))
+	}
+	return value, nil
+}
+
+func (m *LoxMap) SetKey(key interface{}, value interface{}) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	if _, ok := m.Entries[key]; !ok {
+		m.Keys = append(m.Keys, key)
+	}
+	m.Entries[key] = value
+	return nil
+}
+
+// Delete removes key and reports whether it was present.
+func (m *LoxMap) Delete(key interface{}) bool {
+	if _, ok := m.Entries[key]; !ok {
+		return false
+	}
+	delete(m.Entries, key)
+	for i, k := range m.Keys {
+		if k == key {
+			m.Keys = append(m.Keys[:i], m.Keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Get returns the named map method bound to this map.
+func (m *LoxMap) Get(name token.Token) interface{} {
+	switch name.Lexeme {
+	case "len":
+		return loxCallable.NewMethod(name.Lexeme, 0, func(arguments []interface{}) (interface{}, error) {
+			return float64(len(m.Keys)), nil
+		})
+	case "keys":
+		return loxCallable.NewMethod(name.Lexeme, 0, func(arguments []interface{}) (interface{}, error) {
+			keys := make([]interface{}, len(m.Keys))
+			copy(keys, m.Keys)
+			return NewLoxList(keys), nil
+		})
+	case "values":
+		return loxCallable.NewMethod(name.Lexeme, 0, func(arguments []interface{}) (interface{}, error) {
+			values := make([]interface{}, len(m.Keys))
+			for i, key := range m.Keys {
+				values[i] = m.Entries[key]
+			}
+			return NewLoxList(values), nil
+		})
+	case "has":
+		return loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			if err := checkKey(arguments[0]); err != nil {
+				return nil, err
+			}
+			_, ok := m.Entries[arguments[0]]
+			return ok, nil
+		})
+	case "delete":
+		return loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			if err := checkKey(arguments[0]); err != nil {
+				return nil, err
+			}
+			return m.Delete(arguments[0]), nil
+		})
+	}
+
+	panic(loxError.NewRuntimeError(name, name.Lexeme, "Undefined map method '"+name.Lexeme+"'."))
+}
@@ -0,0 +1,40 @@
+package object
+
+import (
+	"github.com/drewslam/goloxTreeInterpreter/environment"
+	"github.com/drewslam/goloxTreeInterpreter/loxError"
+	"github.com/drewslam/goloxTreeInterpreter/token"
+)
+
+// LoxModule is the namespace created by importing a file. Its members are
+// the top-level definitions of the file, held in the module's global
+// environment.
+type LoxModule struct {
+	Name string
+	Env  *environment.Environment
+}
+
+func NewLoxModule(name string, env *environment.Environment) *LoxModule {
+	return &LoxModule{
+		Name: name,
+		Env:  env,
+	}
+}
+
+func (m *LoxModule) String() string {
+	return "<module " + m.Name + ">"
+}
+
+// Member returns the named top-level definition, ignoring natives.
+func (m *LoxModule) Member(name string) (interface{}, bool) {
+	value, exists := m.Env.Values[name]
+	return value, exists
+}
+
+func (m *LoxModule) Get(name token.Token) interface{} {
+	if value, exists := m.Member(name.Lexeme); exists {
+		return value
+	}
+
+	panic(loxError.NewRuntimeError(name, name.Lexeme, "Undefined member '"+name.Lexeme+"' in module '"+m.Name+"'."))
+}
@@ -0,0 +1,189 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/drewslam/goloxTreeInterpreter/loxCallable"
+	"github.com/drewslam/goloxTreeInterpreter/loxError"
+	"github.com/drewslam/goloxTreeInterpreter/token"
+)
+
+// Strings are plain Go strings. Their methods count and index by character
+// (rune) rather than by byte, so non-ASCII text behaves as expected.
+
+// maxRepeatLength bounds the bytes repeat may produce, so a huge count is a
+// runtime error rather than an allocation failure.
+const maxRepeatLength = 1 << 28
+
+// runeIndex converts a Lox value to a character position in a string of
+// length characters. With allowEnd, the position just past the last
+// character is also accepted.
+func runeIndex(value interface{}, length int, allowEnd bool) (int, error) {
+	num, ok := value.(float64)
+	if !ok || num != float64(int(num)) {
+		return 0, fmt.Errorf("String index must be an integer.")
+	}
+
+	index := int(num)
+	limit := length
+	if allowEnd {
+		limit++
+	}
+	if index < 0 || index >= limit {
+		return 0, fmt.Errorf("String index %d out of range for length %d.", index, length)
+	}
+	return index, nil
+}
+
+// StringAt returns the character at a position in str as a string.
+func StringAt(str string, value interface{}) (interface{}, error) {
+	runes := []rune(str)
+	index, err := runeIndex(value, len(runes), false)
+	if err != nil {
+		return nil, err
+	}
+	return string(runes[index]), nil
+}
+
+// stringArg returns the i-th argument of a string method, which must be a
+// string.
+func stringArg(method string, arguments []interface{}, i int) (string, error) {
+	str, ok := arguments[i].(string)
+	if !ok {
+		return "", fmt.Errorf("Argument %d to '%s' must be a string.", i+1, method)
+	}
+	return str, nil
+}
+
+// StringMethod returns the named string method bound to str.
+func StringMethod(str string, name token.Token) interface{} {
+	switch name.Lexeme {
+	case "len":
+		return loxCallable.NewMethod(name.Lexeme, 0, func(arguments []interface{}) (interface{}, error) {
+			return float64(utf8.RuneCountInString(str)), nil
+		})
+	case "upper":
+		return loxCallable.NewMethod(name.Lexeme, 0, func(arguments []interface{}) (interface{}, error) {
+			return strings.ToUpper(str), nil
+		})
+	case "lower":
+		return loxCallable.NewMethod(name.Lexeme, 0, func(arguments []interface{}) (interface{}, error) {
+			return strings.ToLower(str), nil
+		})
+	case "trim":
+		return loxCallable.NewMethod(name.Lexeme, 0, func(arguments []interface{}) (interface{}, error) {
+			return strings.TrimSpace(str), nil
+		})
+	case "split":
+		// An empty separator splits into characters.
+		return loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			sep, err := stringArg("split", arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+			parts := strings.Split(str, sep)
+			elements := make([]interface{}, len(parts))
+			for i, part := range parts {
+				elements[i] = part
+			}
+			return NewLoxList(elements), nil
+		})
+	case "contains":
+		return loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			sub, err := stringArg("contains", arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+			return strings.Contains(str, sub), nil
+		})
+	case "indexOf":
+		// Returns the character position of the first match, or -1.
+		return loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			sub, err := stringArg("indexOf", arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+			index := strings.Index(str, sub)
+			if index < 0 {
+				return float64(-1), nil
+			}
+			return float64(utf8.RuneCountInString(str[:index])), nil
+		})
+	case "replace":
+		// Replaces every occurrence.
+		return loxCallable.NewMethod(name.Lexeme, 2, func(arguments []interface{}) (interface{}, error) {
+			old, err := stringArg("replace", arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+			replacement, err := stringArg("replace", arguments, 1)
+			if err != nil {
+				return nil, err
+			}
+			return strings.ReplaceAll(str, old, replacement), nil
+		})
+	case "substring":
+		// substring(start) or substring(start, end), end exclusive.
+		substring := loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			if len(arguments) > 2 {
+				return nil, fmt.Errorf("Expected 1 or 2 arguments but got %d.", len(arguments))
+			}
+			runes := []rune(str)
+			start, err := runeIndex(arguments[0], len(runes), true)
+			if err != nil {
+				return nil, err
+			}
+			end := len(runes)
+			if len(arguments) == 2 {
+				if end, err = runeIndex(arguments[1], len(runes), true); err != nil {
+					return nil, err
+				}
+			}
+			if end < start {
+				return nil, fmt.Errorf("Substring end %d is before start %d.", end, start)
+			}
+			return string(runes[start:end]), nil
+		})
+		substring.Variadic = true
+		return substring
+	case "startsWith":
+		return loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			prefix, err := stringArg("startsWith", arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+			return strings.HasPrefix(str, prefix), nil
+		})
+	case "endsWith":
+		return loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			suffix, err := stringArg("endsWith", arguments, 0)
+			if err != nil {
+				return nil, err
+			}
+			return strings.HasSuffix(str, suffix), nil
+		})
+	case "repeat":
+		return loxCallable.NewMethod(name.Lexeme, 1, func(arguments []interface{}) (interface{}, error) {
+			count, ok := arguments[0].(float64)
+			if !ok || count != float64(int(count)) || count < 0 {
+				return nil, fmt.Errorf("Repeat count must be a non-negative integer.")
+			}
+			if len(str) > 0 && count > float64(maxRepeatLength/len(str)) {
+				return nil, fmt.Errorf("Repeated string would be longer than %d bytes.", maxRepeatLength)
+			}
+			return strings.Repeat(str, int(count)), nil
+		})
+	case "chars":
+		return loxCallable.NewMethod(name.Lexeme, 0, func(arguments []interface{}) (interface{}, error) {
+			var chars []interface{}
+			for _, r := range str {
+				chars = append(chars, string(r))
+			}
+			return NewLoxList(chars), nil
+		})
+	}
+
+	panic(loxError.NewRuntimeError(name, name.Lexeme, "Undefined string method '"+name.Lexeme+"'."))
+}
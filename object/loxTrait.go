@@ -0,0 +1,12 @@
+package object
+
+// LoxTrait is a named set of methods that classes include with 'with'. The
+// methods are bound to instances of those classes like their own methods.
+type LoxTrait struct {
+	Name    string
+	Methods map[string]*LoxFunction
+}
+
+func (t *LoxTrait) String() string {
+	return "<trait " + t.Name + ">"
+}
@@ -11,6 +11,7 @@ import (
 type Parser struct {
 	tokens  []token.Token
 	current int
+	err     *loxError.LoxError
 }
 
 func NewParser(tokens []token.Token) *Parser {
@@ -40,12 +41,28 @@ func (p *Parser) declaration() (ast.Stmt, *loxError.LoxError) {
 	if p.match(token.CLASS) {
 		return p.classDeclaration()
 	}
-	if p.match(token.FUN) {
+	if p.match(token.TRAIT) {
+		return p.traitDeclaration()
+	}
+	if p.match(token.INTERFACE) {
+		return p.interfaceDeclaration()
+	}
+	// A 'fun' without a name starts an anonymous function expression.
+	if p.check(token.FUN) && p.checkNext(token.IDENTIFIER) {
+		p.advance()
 		return p.function("function")
 	}
 	if p.match(token.VAR) {
 		return p.varDeclaration()
 	}
+	if p.match(token.IMPORT) {
+		return p.importDeclaration()
+	}
+	// 'from' is only special when followed by a module path.
+	if p.checkContextual("from") && p.checkNext(token.STRING) {
+		p.advance()
+		return p.fromImportDeclaration()
+	}
 
 	return p.statement()
 }
@@ -61,11 +78,61 @@ func (p *Parser) classDeclaration() (ast.Stmt, *loxError.LoxError) {
 		}
 	}
 
+	var traits []*ast.Variable
+	if p.matchContextual("with") {
+		for {
+			traits = append(traits, &ast.Variable{
+				Name: p.consume(token.IDENTIFIER, "Expect trait name."),
+			})
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	var interfaces []*ast.Variable
+	if p.matchContextual("implements") {
+		for {
+			interfaces = append(interfaces, &ast.Variable{
+				Name: p.consume(token.IDENTIFIER, "Expect interface name."),
+			})
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+	}
+
 	p.consume(token.LEFT_BRACE, "Expect '{' before class body.")
 
 	var methods []*ast.Function
+	var classMethods []*ast.Function
 	for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
-		if !p.check(token.IDENTIFIER) {
+		if p.match(token.ABSTRACT) {
+			method, err := p.abstractMethod()
+			if err != nil {
+				return nil, err
+			}
+			if method.Name.Type == token.PRIVATE_IDENTIFIER {
+				return nil, loxError.NewParseError(method.Name, "Abstract methods can't be private.")
+			}
+			if method.Name.Lexeme == "init" {
+				return nil, loxError.NewParseError(method.Name, "Initializers can't be abstract.")
+			}
+			methods = append(methods, method)
+			continue
+		}
+		if p.match(token.CLASS) {
+			method, err := p.function("method")
+			if err != nil {
+				return nil, err
+			}
+			if method.Name.Type == token.PRIVATE_IDENTIFIER {
+				return nil, loxError.NewParseError(method.Name, "Class methods can't be private.")
+			}
+			classMethods = append(classMethods, method)
+			continue
+		}
+		if !p.check(token.IDENTIFIER) && !p.check(token.PRIVATE_IDENTIFIER) {
 			// err := loxError.NewParseError(p.peek(), "Only methods are allowed in class bodies.")
 			p.synchronize()
 			continue
@@ -82,13 +149,132 @@ func (p *Parser) classDeclaration() (ast.Stmt, *loxError.LoxError) {
 
 	p.consume(token.RIGHT_BRACE, "Expect '}' after class body.")
 	return &ast.Class{
+		Name:         name,
+		Superclass:   superclass,
+		Traits:       traits,
+		Interfaces:   interfaces,
+		Methods:      methods,
+		ClassMethods: classMethods,
+	}, nil
+}
+
+func (p *Parser) importDeclaration() (ast.Stmt, *loxError.LoxError) {
+	keyword := p.previous()
+	path := p.consume(token.STRING, "Expect module path after 'import'.")
+	if !p.matchContextual("as") {
+		return nil, loxError.NewParseError(p.peek(), "Expect 'as' after module path.")
+	}
+	alias := p.consume(token.IDENTIFIER, "Expect module name after 'as'.")
+	p.consume(token.SEMICOLON, "Expect ';' after import.")
+	return &ast.Import{
+		Keyword: keyword,
+		Path:    path,
+		Alias:   alias,
+	}, nil
+}
+
+func (p *Parser) fromImportDeclaration() (ast.Stmt, *loxError.LoxError) {
+	path := p.consume(token.STRING, "Expect module path after 'from'.")
+	keyword := p.consume(token.IMPORT, "Expect 'import' after module path.")
+
+	var names []token.Token
+	for {
+		names = append(names, p.consume(token.IDENTIFIER, "Expect name to import."))
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+
+	p.consume(token.SEMICOLON, "Expect ';' after import.")
+	return &ast.Import{
+		Keyword: keyword,
+		Path:    path,
+		Names:   names,
+	}, nil
+}
+
+func (p *Parser) traitDeclaration() (ast.Stmt, *loxError.LoxError) {
+	name := p.consume(token.IDENTIFIER, "Expect trait name.")
+	p.consume(token.LEFT_BRACE, "Expect '{' before trait body.")
+
+	var methods []*ast.Function
+	for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
+		method, err := p.function("method")
+		if err != nil {
+			return nil, err
+		}
+		if method.Name.Type == token.PRIVATE_IDENTIFIER {
+			return nil, loxError.NewParseError(method.Name, "Traits can't have private methods.")
+		}
+		methods = append(methods, method)
+	}
+
+	p.consume(token.RIGHT_BRACE, "Expect '}' after trait body.")
+	return &ast.Trait{
+		Name:    name,
+		Methods: methods,
+	}, nil
+}
+
+func (p *Parser) interfaceDeclaration() (ast.Stmt, *loxError.LoxError) {
+	name := p.consume(token.IDENTIFIER, "Expect interface name.")
+	p.consume(token.LEFT_BRACE, "Expect '{' before interface body.")
+
+	var methods []*ast.Function
+	for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
+		method, err := p.abstractMethod()
+		if err != nil {
+			return nil, err
+		}
+		if method.Name.Type == token.PRIVATE_IDENTIFIER {
+			return nil, loxError.NewParseError(method.Name, "Interfaces can't require private methods.")
+		}
+		methods = append(methods, method)
+	}
+
+	p.consume(token.RIGHT_BRACE, "Expect '}' after interface body.")
+	return &ast.Interface{
+		Name:    name,
+		Methods: methods,
+	}, nil
+}
+
+// abstractMethod parses a method declaration without a body: a name, a
+// parameter list unless it is a getter, and a semicolon.
+func (p *Parser) abstractMethod() (*ast.Function, *loxError.LoxError) {
+	var name token.Token
+	if p.match(token.PRIVATE_IDENTIFIER) {
+		name = p.previous()
+	} else {
+		name = p.consume(token.IDENTIFIER, "Expect method name.")
+	}
+
+	isGetter := !p.match(token.LEFT_PAREN)
+	var parameters []token.Token
+	if !isGetter {
+		params, err := p.parameters()
+		if err != nil {
+			return nil, err
+		}
+		parameters = params
+	}
+
+	p.consume(token.SEMICOLON, "Expect ';' after abstract method.")
+	return &ast.Function{
 		Name:       name,
-		Superclass: superclass,
-		Methods:    methods,
+		Params:     parameters,
+		IsGetter:   isGetter,
+		IsAbstract: true,
 	}, nil
 }
 
 func (p *Parser) statement() (ast.Stmt, *loxError.LoxError) {
+	if p.match(token.BREAK) {
+		return p.breakStatement()
+	}
+	if p.match(token.CONTINUE) {
+		return p.continueStatement()
+	}
 	if p.match(token.FOR) {
 		return p.forStatement()
 	}
@@ -101,6 +287,12 @@ func (p *Parser) statement() (ast.Stmt, *loxError.LoxError) {
 	if p.match(token.RETURN) {
 		return p.returnStatement()
 	}
+	if p.match(token.THROW) {
+		return p.throwStatement()
+	}
+	if p.match(token.TRY) {
+		return p.tryStatement()
+	}
 	if p.match(token.WHILE) {
 		return p.whileStatement()
 	}
@@ -115,7 +307,24 @@ func (p *Parser) statement() (ast.Stmt, *loxError.LoxError) {
 	return p.expressionStatement()
 }
 
+func (p *Parser) breakStatement() (ast.Stmt, *loxError.LoxError) {
+	keyword := p.previous()
+	p.consume(token.SEMICOLON, "Expect ';' after 'break'.")
+	return &ast.Break{
+		Keyword: keyword,
+	}, nil
+}
+
+func (p *Parser) continueStatement() (ast.Stmt, *loxError.LoxError) {
+	keyword := p.previous()
+	p.consume(token.SEMICOLON, "Expect ';' after 'continue'.")
+	return &ast.Continue{
+		Keyword: keyword,
+	}, nil
+}
+
 func (p *Parser) forStatement() (ast.Stmt, *loxError.LoxError) {
+	keyword := p.previous()
 	p.consume(token.LEFT_PAREN, "Expect '(' after 'for'.")
 
 	var initializer ast.Stmt
@@ -160,15 +369,6 @@ func (p *Parser) forStatement() (ast.Stmt, *loxError.LoxError) {
 		return nil, err
 	}
 
-	if increment != nil {
-		body = &ast.Block{
-			Statements: []ast.Stmt{
-				body,
-				&ast.Expression{Expr: increment},
-			},
-		}
-	}
-
 	if condition == nil {
 		condition = &ast.Literal{
 			Value: true,
@@ -176,8 +376,10 @@ func (p *Parser) forStatement() (ast.Stmt, *loxError.LoxError) {
 	}
 
 	body = &ast.While{
+		Keyword:   keyword,
 		Condition: condition,
 		Body:      body,
+		Increment: increment,
 	}
 
 	if initializer != nil {
@@ -244,6 +446,56 @@ func (p *Parser) returnStatement() (ast.Stmt, *loxError.LoxError) {
 	}, nil
 }
 
+func (p *Parser) throwStatement() (ast.Stmt, *loxError.LoxError) {
+	keyword := p.previous()
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	p.consume(token.SEMICOLON, "Expect ';' after thrown value.")
+	return &ast.Throw{
+		Keyword: keyword,
+		Value:   value,
+	}, nil
+}
+
+func (p *Parser) tryStatement() (ast.Stmt, *loxError.LoxError) {
+	keyword := p.previous()
+	p.consume(token.LEFT_BRACE, "Expect '{' after 'try'.")
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	stmt := &ast.Try{Body: ast.NewBlockStmt(body)}
+
+	if p.match(token.CATCH) {
+		p.consume(token.LEFT_PAREN, "Expect '(' after 'catch'.")
+		stmt.CatchName = p.consume(token.IDENTIFIER, "Expect exception variable name.")
+		p.consume(token.RIGHT_PAREN, "Expect ')' after exception variable.")
+		p.consume(token.LEFT_BRACE, "Expect '{' before catch body.")
+		catch, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Catch = ast.NewBlockStmt(catch)
+	}
+
+	if p.match(token.FINALLY) {
+		p.consume(token.LEFT_BRACE, "Expect '{' after 'finally'.")
+		finally, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Finally = ast.NewBlockStmt(finally)
+	}
+
+	if stmt.Catch == nil && stmt.Finally == nil {
+		return nil, loxError.NewParseError(keyword, "Expect 'catch' or 'finally' after try block.")
+	}
+	return stmt, nil
+}
+
 func (p *Parser) varDeclaration() (ast.Stmt, *loxError.LoxError) {
 	name := p.consume(token.IDENTIFIER, "Expect variable name.")
 
@@ -261,6 +513,7 @@ func (p *Parser) varDeclaration() (ast.Stmt, *loxError.LoxError) {
 }
 
 func (p *Parser) whileStatement() (ast.Stmt, *loxError.LoxError) {
+	keyword := p.previous()
 	p.consume(token.LEFT_PAREN, "Expect '(' after 'while'.")
 	condition, err := p.expression()
 	if err != nil {
@@ -274,6 +527,7 @@ func (p *Parser) whileStatement() (ast.Stmt, *loxError.LoxError) {
 	}
 
 	return &ast.While{
+		Keyword:   keyword,
 		Condition: condition,
 		Body:      body,
 	}, nil
@@ -290,10 +544,44 @@ func (p *Parser) expressionStatement() (ast.Stmt, *loxError.LoxError) {
 
 func (p *Parser) function(kind string) (*ast.Function, *loxError.LoxError) {
 	message := fmt.Sprintf("Expect %v name.", kind)
-	name := p.consume(token.IDENTIFIER, message)
+	var name token.Token
+	if kind == "method" && p.match(token.PRIVATE_IDENTIFIER) {
+		name = p.previous()
+	} else {
+		name = p.consume(token.IDENTIFIER, message)
+	}
+
+	// A method without a parameter list is a getter.
+	isGetter := kind == "method" && p.check(token.LEFT_BRACE)
+
+	var parameters []token.Token
+	if !isGetter {
+		p.consume(token.LEFT_PAREN, "Expect '(' after function name.")
+
+		params, err := p.parameters()
+		if err != nil {
+			return nil, err
+		}
+		parameters = params
+	}
 
-	p.consume(token.LEFT_PAREN, "Expect '(' after function name.")
+	message = fmt.Sprintf("Expect '{' before %v body.", kind)
+	p.consume(token.LEFT_BRACE, message)
 
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	return &ast.Function{
+		Name:     name,
+		Params:   parameters,
+		Body:     body,
+		IsGetter: isGetter,
+	}, nil
+}
+
+// parameters parses a parameter list after its '(' up to and including ')'.
+func (p *Parser) parameters() ([]token.Token, *loxError.LoxError) {
 	var parameters []token.Token
 	if !p.check(token.RIGHT_PAREN) {
 		for {
@@ -308,19 +596,79 @@ func (p *Parser) function(kind string) (*ast.Function, *loxError.LoxError) {
 	}
 
 	p.consume(token.RIGHT_PAREN, "Expect ')' after parameters.")
+	return parameters, nil
+}
 
-	message = fmt.Sprintf("Expect '{' before %v body.", kind)
-	p.consume(token.LEFT_BRACE, message)
+// lambda parses "fun (params) { body }" after the 'fun'.
+func (p *Parser) lambda() (ast.Expr, *loxError.LoxError) {
+	keyword := p.previous()
+	p.consume(token.LEFT_PAREN, "Expect '(' after 'fun'.")
 
+	parameters, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+
+	p.consume(token.LEFT_BRACE, "Expect '{' before function body.")
 	body, err := p.block()
 	if err != nil {
 		return nil, err
 	}
-	return &ast.Function{
-		Name:   name,
-		Params: parameters,
-		Body:   body,
-	}, nil
+	return newLambda(keyword, parameters, body), nil
+}
+
+// isArrowFunction looks past a parenthesized identifier list for '=>'.
+func (p *Parser) isArrowFunction() bool {
+	i := p.current
+	if p.tokens[i].Type != token.LEFT_PAREN {
+		return false
+	}
+	i++
+
+	if p.tokens[i].Type == token.IDENTIFIER {
+		i++
+		for p.tokens[i].Type == token.COMMA && p.tokens[i+1].Type == token.IDENTIFIER {
+			i += 2
+		}
+	}
+
+	return p.tokens[i].Type == token.RIGHT_PAREN && p.tokens[i+1].Type == token.ARROW
+}
+
+// arrowFunction parses "(params) => expr" or "(params) => { body }".
+func (p *Parser) arrowFunction() (ast.Expr, *loxError.LoxError) {
+	p.consume(token.LEFT_PAREN, "Expect '(' before parameters.")
+	parameters, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+	arrow := p.consume(token.ARROW, "Expect '=>' after parameters.")
+
+	if p.match(token.LEFT_BRACE) {
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		return newLambda(arrow, parameters, body), nil
+	}
+
+	value, err := p.assignment()
+	if err != nil {
+		return nil, err
+	}
+	body := []ast.Stmt{&ast.Return{Keyword: arrow, Value: value}}
+	return newLambda(arrow, parameters, body), nil
+}
+
+func newLambda(keyword token.Token, parameters []token.Token, body []ast.Stmt) *ast.Lambda {
+	return &ast.Lambda{
+		Keyword: keyword,
+		Function: &ast.Function{
+			Name:   token.Token{Type: token.IDENTIFIER, Lexeme: "", Line: keyword.Line},
+			Params: parameters,
+			Body:   body,
+		},
+	}
 }
 
 func (p *Parser) block() ([]ast.Stmt, *loxError.LoxError) {
@@ -375,6 +723,13 @@ func (p *Parser) assignment() (ast.Expr, *loxError.LoxError) {
 				Name:   v.Name,
 				Value:  value,
 			}, nil
+		case *ast.Index:
+			return &ast.IndexSet{
+				Object:  v.Object,
+				Bracket: v.Bracket,
+				Index:   v.Index,
+				Value:   value,
+			}, nil
 		case *ast.This:
 			return nil, loxError.NewParseError(v.Keyword, "Cannot assign to 'this'.")
 		default:
@@ -459,7 +814,7 @@ func (p *Parser) comparison() (ast.Expr, *loxError.LoxError) {
 		return nil, err
 	}
 
-	for p.match(token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL) {
+	for p.match(token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL, token.IS) {
 		operator := p.previous()
 		right, err := p.term()
 		if err != nil {
@@ -579,11 +934,28 @@ func (p *Parser) call() (ast.Expr, *loxError.LoxError) {
 				return nil, err
 			}
 		} else if p.match(token.DOT) {
-			name := p.consume(token.IDENTIFIER, "Expect property name after '.'.")
+			var name token.Token
+			if p.match(token.PRIVATE_IDENTIFIER) {
+				name = p.previous()
+			} else {
+				name = p.consume(token.IDENTIFIER, "Expect property name after '.'.")
+			}
 			expr = &ast.Get{
 				Object: expr,
 				Name:   name,
 			}
+		} else if p.match(token.LEFT_BRACKET) {
+			bracket := p.previous()
+			index, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			p.consume(token.RIGHT_BRACKET, "Expect ']' after index.")
+			expr = &ast.Index{
+				Object:  expr,
+				Bracket: bracket,
+				Index:   index,
+			}
 		} else {
 			break
 		}
@@ -592,6 +964,64 @@ func (p *Parser) call() (ast.Expr, *loxError.LoxError) {
 	return expr, nil
 }
 
+func (p *Parser) list() (ast.Expr, *loxError.LoxError) {
+	bracket := p.previous()
+
+	var elements []ast.Expr
+	if !p.check(token.RIGHT_BRACKET) {
+		for {
+			element, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	p.consume(token.RIGHT_BRACKET, "Expect ']' after list elements.")
+	return &ast.List{
+		Bracket:  bracket,
+		Elements: elements,
+	}, nil
+}
+
+func (p *Parser) mapLiteral() (ast.Expr, *loxError.LoxError) {
+	brace := p.previous()
+
+	var keys []ast.Expr
+	var values []ast.Expr
+	if !p.check(token.RIGHT_BRACE) {
+		for {
+			key, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			p.consume(token.COLON, "Expect ':' after map key.")
+			value, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			values = append(values, value)
+
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	p.consume(token.RIGHT_BRACE, "Expect '}' after map entries.")
+	return &ast.Map{
+		Brace:  brace,
+		Keys:   keys,
+		Values: values,
+	}, nil
+}
+
 func (p *Parser) primary() (ast.Expr, *loxError.LoxError) {
 	if p.match(token.FALSE) {
 		return &ast.Literal{
@@ -637,6 +1067,14 @@ func (p *Parser) primary() (ast.Expr, *loxError.LoxError) {
 		}, nil
 	}
 
+	if p.match(token.FUN) {
+		return p.lambda()
+	}
+
+	if p.isArrowFunction() {
+		return p.arrowFunction()
+	}
+
 	if p.match(token.LEFT_PAREN) {
 		expr, err := p.expression()
 		if err != nil {
@@ -649,6 +1087,14 @@ func (p *Parser) primary() (ast.Expr, *loxError.LoxError) {
 		}, nil
 	}
 
+	if p.match(token.LEFT_BRACKET) {
+		return p.list()
+	}
+
+	if p.match(token.LEFT_BRACE) {
+		return p.mapLiteral()
+	}
+
 	// Error handling if no valid expression is found
 	return nil, loxError.NewParseError(p.peek(), "Expect expression.")
 }
@@ -672,6 +1118,10 @@ func (p *Parser) Parse() (statements []ast.Stmt, err *loxError.LoxError) {
 	for !p.isAtEnd() {
 		stmt, err := p.declaration()
 		if err != nil {
+			// An error recorded by consume came first.
+			if p.err != nil {
+				return nil, p.err
+			}
 			return nil, err
 			// p.synchronize()
 			// continue
@@ -680,11 +1130,9 @@ func (p *Parser) Parse() (statements []ast.Stmt, err *loxError.LoxError) {
 			statements = append(statements, stmt)
 		}
 	}
-	/*
-		if err != nil {
-			return nil, err
-		}
-	*/
+	if p.err != nil {
+		return nil, p.err
+	}
 	return // statements, nil
 }
 
@@ -703,11 +1151,11 @@ func (p *Parser) consume(expected token.TokenType, message string) token.Token {
 		return p.advance()
 	}
 
-	err := loxError.NewParseError(p.peek(), message)
-	// if err != nil {
-	//	panic(err)
-	// }
-	loxError.ReportError(err)
+	// Keep the first error for Parse to return and recover so parsing can
+	// continue past it.
+	if p.err == nil {
+		p.err = loxError.NewParseError(p.peek(), message)
+	}
 	p.synchronize()
 
 	return token.Token{}
@@ -721,6 +1169,32 @@ func (p *Parser) check(tokentype token.TokenType) bool {
 	return p.peek().Type == tokentype
 }
 
+// checkContextual reports whether the next token is the contextual keyword
+// word. 'with', 'implements', 'as' and 'from' are scanned as identifiers and
+// only act as keywords where the grammar expects them, so they stay usable
+// as names everywhere else.
+func (p *Parser) checkContextual(word string) bool {
+	return p.check(token.IDENTIFIER) && p.peek().Lexeme == word
+}
+
+// matchContextual consumes the next token if it is the contextual keyword
+// word.
+func (p *Parser) matchContextual(word string) bool {
+	if !p.checkContextual(word) {
+		return false
+	}
+	p.advance()
+	return true
+}
+
+func (p *Parser) checkNext(tokentype token.TokenType) bool {
+	if p.isAtEnd() || p.tokens[p.current+1].Type == token.EOF {
+		return false
+	}
+
+	return p.tokens[p.current+1].Type == tokentype
+}
+
 func (p *Parser) advance() token.Token {
 	if !p.isAtEnd() {
 		p.current++
@@ -749,7 +1223,7 @@ func (p *Parser) synchronize() {
 		}
 
 		switch p.peek().Type {
-		case token.CLASS, token.FUN, token.VAR, token.FOR, token.IF, token.WHILE, token.PRINT, token.RETURN:
+		case token.CLASS, token.FUN, token.VAR, token.FOR, token.IF, token.WHILE, token.PRINT, token.RETURN, token.BREAK, token.CONTINUE, token.THROW, token.TRY, token.IMPORT, token.TRAIT, token.INTERFACE:
 			return
 		case token.RIGHT_BRACE: // Recover at class/method boundaries
 			p.advance()
@@ -0,0 +1,118 @@
+// Command regression runs Lox scripts through the golox package and checks
+// their output against the expectations written in them. Each line a script
+// prints must match a "// expect: text" comment, in order, and a script
+// that should stop with a runtime error ends with an
+// "// expect runtime error: message" comment. "// stdin: text" comments
+// give the lines of input the script reads, and a "// max call depth: n"
+// comment runs the script with that call depth limit.
+//
+// Scripts may use the file natives and imports within their own directory.
+// go test runs every script in this directory.
+//
+// Usage: go run ./regression regression/*.lox
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/drewslam/goloxTreeInterpreter/golox"
+	"github.com/drewslam/goloxTreeInterpreter/interpreter"
+	"github.com/drewslam/goloxTreeInterpreter/loxError"
+)
+
+const (
+	expectPrefix      = "// expect: "
+	expectErrorPrefix = "// expect runtime error: "
+	stdinPrefix       = "// stdin: "
+	maxDepthPrefix    = "// max call depth: "
+)
+
+func main() {
+	failed := 0
+	for _, path := range os.Args[1:] {
+		if err := check(path); err != nil {
+			fmt.Printf("FAIL %s: %v\n", path, err)
+			failed++
+			continue
+		}
+		fmt.Printf("ok   %s\n", path)
+	}
+
+	if failed > 0 {
+		fmt.Printf("%d of %d scripts failed\n", failed, len(os.Args)-1)
+		os.Exit(1)
+	}
+}
+
+// check runs the script at path and compares what it did with what its
+// comments expect.
+func check(path string) error {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var expected []string
+	var input strings.Builder
+	var limits interpreter.Limits
+	expectedError := ""
+	scanner := bufio.NewScanner(bytes.NewReader(source))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.Index(line, expectPrefix); index >= 0 {
+			expected = append(expected, line[index+len(expectPrefix):])
+		} else if index := strings.Index(line, expectErrorPrefix); index >= 0 {
+			expectedError = line[index+len(expectErrorPrefix):]
+		} else if index := strings.Index(line, stdinPrefix); index >= 0 {
+			input.WriteString(line[index+len(stdinPrefix):] + "\n")
+		} else if index := strings.Index(line, maxDepthPrefix); index >= 0 {
+			depth, err := strconv.Atoi(line[index+len(maxDepthPrefix):])
+			if err != nil {
+				return fmt.Errorf("invalid call depth: %v", err)
+			}
+			limits.MaxCallDepth = depth
+		}
+	}
+
+	var out bytes.Buffer
+	vm := golox.New()
+	vm.SetStdout(&out)
+	vm.SetStdin(strings.NewReader(input.String()))
+	vm.SetLimits(limits)
+	vm.SetFileAccess(interpreter.FileAccess{Enabled: true, Root: filepath.Dir(path)})
+	runErr := vm.RunFile(path)
+
+	var loxErr *loxError.LoxError
+	switch {
+	case runErr == nil && expectedError != "":
+		return fmt.Errorf("expected runtime error %q", expectedError)
+	case runErr != nil && !errors.As(runErr, &loxErr):
+		return runErr
+	case runErr != nil && loxErr.Message != expectedError:
+		return fmt.Errorf("unexpected error: %v", runErr)
+	}
+
+	actual := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if out.Len() == 0 {
+		actual = nil
+	}
+	for index, want := range expected {
+		if index >= len(actual) {
+			return fmt.Errorf("missing output %q", want)
+		}
+		if actual[index] != want {
+			return fmt.Errorf("output line %d is %q, expected %q", index+1, actual[index], want)
+		}
+	}
+	if len(actual) > len(expected) {
+		return fmt.Errorf("unexpected output %q", actual[len(expected)])
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestScripts(t *testing.T) {
+	paths, err := filepath.Glob("*.lox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no scripts found")
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			if err := check(path); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
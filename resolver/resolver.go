@@ -4,24 +4,41 @@ import (
 	"fmt"
 
 	"github.com/drewslam/goloxTreeInterpreter/ast"
-	"github.com/drewslam/goloxTreeInterpreter/interpreter"
 	"github.com/drewslam/goloxTreeInterpreter/loxDebug"
 	"github.com/drewslam/goloxTreeInterpreter/loxError"
 	"github.com/drewslam/goloxTreeInterpreter/token"
 )
 
-func peek(stack []map[string]bool) (map[string]bool, bool) {
+func peek(stack []map[string]*variable) (map[string]*variable, bool) {
 	if len(stack) == 0 {
 		return nil, false
 	}
 	return stack[len(stack)-1], true
 }
 
+// variable tracks a local declared in a scope: the environment slot it will
+// occupy at runtime and whether its initializer has finished.
+type variable struct {
+	slot    int
+	defined bool
+}
+
+// Interpreter receives the location of each local variable reference and
+// the class declaring each private member access. It is an interface so the
+// interpreter can use the resolver to load modules.
+type Interpreter interface {
+	Resolve(expr ast.Expr, depth int, slot int)
+	ResolvePrivate(expr ast.Expr, owner *ast.Class)
+}
+
 type Resolver struct {
-	Interpreter     *interpreter.Interpreter
-	scopes          []map[string]bool
+	Interpreter     Interpreter
+	scopes          []map[string]*variable
 	CurrentFunction FunctionType
 	currentClass    ClassType
+	classDecl       *ast.Class
+	loopDepth       int
+	err             *loxError.LoxError
 }
 
 type FunctionType int
@@ -33,10 +50,10 @@ const (
 	METHOD
 )
 
-func NewResolver(interpreter *interpreter.Interpreter) *Resolver {
+func NewResolver(interpreter Interpreter) *Resolver {
 	return &Resolver{
 		Interpreter:     interpreter,
-		scopes:          make([]map[string]bool, 0),
+		scopes:          make([]map[string]*variable, 0),
 		CurrentFunction: NOT_FUNCTION,
 		currentClass:    NOT_CLASS,
 	}
@@ -48,6 +65,9 @@ const (
 	NOT_CLASS ClassType = iota
 	CLASS
 	SUBCLASS
+	// CLASS_METHOD is the body of a class method, which has no instance.
+	CLASS_METHOD
+	TRAIT
 )
 
 // var currentClass ClassType = NOT_CLASS
@@ -55,11 +75,12 @@ const (
 var _ ast.StmtVisitor = (*Resolver)(nil)
 var _ ast.ExprVisitor = (*Resolver)(nil)
 
+// Resolve resolves the statements and returns the first error found.
 func (r *Resolver) Resolve(statements []ast.Stmt) *loxError.LoxError {
 	for _, statement := range statements {
 		r.resolve(statement)
 	}
-	return nil
+	return r.err
 }
 
 func (r *Resolver) VisitBlockStmt(stmt *ast.Block) interface{} {
@@ -69,17 +90,31 @@ func (r *Resolver) VisitBlockStmt(stmt *ast.Block) interface{} {
 	return nil
 }
 
+func (r *Resolver) VisitBreakStmt(stmt *ast.Break) interface{} {
+	if r.loopDepth == 0 {
+		return loxError.NewParseError(stmt.Keyword, "Can't use 'break' outside of a loop.")
+	}
+	return nil
+}
+
 func (r *Resolver) VisitClassStmt(stmt *ast.Class) interface{} {
 	enclosingClass := r.currentClass
 	r.currentClass = CLASS
+	enclosingDecl := r.classDecl
+	r.classDecl = stmt
 
 	r.declare(stmt.Name)
 	r.define(stmt.Name)
 
 	if stmt.Superclass != nil && stmt.Name.Lexeme == stmt.Superclass.Name.Lexeme {
-		err := loxError.NewParseError(stmt.Superclass.Name, "A class cannot inherit from itself.")
-		loxError.ReportError(err)
-		return nil
+		return loxError.NewParseError(stmt.Superclass.Name, "A class cannot inherit from itself.")
+	}
+
+	for _, trait := range stmt.Traits {
+		r.resolve(trait)
+	}
+	for _, iface := range stmt.Interfaces {
+		r.resolve(iface)
 	}
 
 	if stmt.Superclass != nil {
@@ -87,13 +122,11 @@ func (r *Resolver) VisitClassStmt(stmt *ast.Class) interface{} {
 		r.resolve(stmt.Superclass)
 
 		r.beginScope()
-		r.scopes[len(r.scopes)-1]["super"] = true
+		r.declareSynthetic("super")
 	}
 
 	r.beginScope()
-	if len(r.scopes) > 0 {
-		r.scopes[len(r.scopes)-1]["this"] = true
-	}
+	r.declareSynthetic("this")
 
 	for _, method := range stmt.Methods {
 		declaration := METHOD
@@ -108,7 +141,22 @@ func (r *Resolver) VisitClassStmt(stmt *ast.Class) interface{} {
 		r.endScope()
 	}
 
+	// Class methods close over the scope the class is declared in, with
+	// neither 'this' nor 'super'.
+	r.currentClass = CLASS_METHOD
+	for _, method := range stmt.ClassMethods {
+		r.resolveFunction(method, METHOD)
+	}
+
 	r.currentClass = enclosingClass
+	r.classDecl = enclosingDecl
+	return nil
+}
+
+func (r *Resolver) VisitContinueStmt(stmt *ast.Continue) interface{} {
+	if r.loopDepth == 0 {
+		return loxError.NewParseError(stmt.Keyword, "Can't use 'continue' outside of a loop.")
+	}
 	return nil
 }
 
@@ -134,6 +182,25 @@ func (r *Resolver) VisitIfStmt(stmt *ast.If) interface{} {
 	return nil
 }
 
+func (r *Resolver) VisitImportStmt(stmt *ast.Import) interface{} {
+	if len(stmt.Names) == 0 {
+		r.declare(stmt.Alias)
+		r.define(stmt.Alias)
+		return nil
+	}
+	for _, name := range stmt.Names {
+		r.declare(name)
+		r.define(name)
+	}
+	return nil
+}
+
+func (r *Resolver) VisitInterfaceStmt(stmt *ast.Interface) interface{} {
+	r.declare(stmt.Name)
+	r.define(stmt.Name)
+	return nil
+}
+
 func (r *Resolver) VisitPrintStmt(stmt *ast.Print) interface{} {
 	r.resolve(stmt.Expr)
 	return nil
@@ -154,49 +221,103 @@ func (r *Resolver) VisitReturnStmt(stmt *ast.Return) interface{} {
 	return nil
 }
 
-func (r *Resolver) resolve(input interface{}) {
-	if len(r.scopes) == 0 {
+func (r *Resolver) VisitThrowStmt(stmt *ast.Throw) interface{} {
+	r.resolve(stmt.Value)
+	return nil
+}
+
+func (r *Resolver) VisitTraitStmt(stmt *ast.Trait) interface{} {
+	r.declare(stmt.Name)
+	r.define(stmt.Name)
+
+	enclosingClass := r.currentClass
+	r.currentClass = TRAIT
+	enclosingDecl := r.classDecl
+	r.classDecl = nil
+
+	// Trait methods are bound to instances like class methods are.
+	r.beginScope()
+	r.declareSynthetic("this")
+
+	for _, method := range stmt.Methods {
+		declaration := METHOD
+		if method.Name.Lexeme == "init" {
+			declaration = INITIALIZER
+		}
+		r.resolveFunction(method, declaration)
+	}
+	r.endScope()
+
+	r.currentClass = enclosingClass
+	r.classDecl = enclosingDecl
+	return nil
+}
+
+func (r *Resolver) VisitTryStmt(stmt *ast.Try) interface{} {
+	r.resolve(stmt.Body)
+
+	if stmt.Catch != nil {
+		// The exception variable gets its own scope around the catch body,
+		// matching the environment the interpreter binds it in.
 		r.beginScope()
+		r.declare(stmt.CatchName)
+		r.define(stmt.CatchName)
+		r.resolve(stmt.Catch)
+		r.endScope()
+	}
+
+	if stmt.Finally != nil {
+		r.resolve(stmt.Finally)
 	}
+	return nil
+}
 
+func (r *Resolver) resolve(input interface{}) {
+	var result interface{}
 	switch v := input.(type) {
 	case ast.Stmt:
-		v.Accept(r)
+		result = v.Accept(r)
 	case ast.Expr:
-		v.Accept(r)
+		result = v.Accept(r)
 	default:
 	}
+
+	// Visitors report problems by returning them.
+	if err, ok := result.(*loxError.LoxError); ok && err != nil {
+		r.report(err)
+	}
+}
+
+// report keeps the first error for Resolve to return.
+func (r *Resolver) report(err *loxError.LoxError) {
+	if r.err == nil {
+		r.err = err
+	}
 }
 
 func (r *Resolver) resolveFunction(function *ast.Function, functiontype FunctionType) {
 	enclosingFunction := r.CurrentFunction
 	r.CurrentFunction = functiontype
+	// Loops outside the function can't be broken out of from inside it.
+	enclosingLoopDepth := r.loopDepth
+	r.loopDepth = 0
 
+	// Parameters and the body share one scope, as they share one
+	// environment when the function is called.
 	r.beginScope()
-
-	if r.currentClass != NOT_CLASS {
-		r.scopes[len(r.scopes)-1]["this"] = true
-		if r.currentClass == SUBCLASS {
-			r.scopes[len(r.scopes)-1]["super"] = true
-		}
-	}
-
 	for _, param := range function.Params {
 		r.declare(param)
 		r.define(param)
 	}
-	r.resolve(function.Body)
+	r.Resolve(function.Body)
 	r.endScope()
 
 	r.CurrentFunction = enclosingFunction
+	r.loopDepth = enclosingLoopDepth
 }
 
 func (r *Resolver) beginScope() {
-	if r.scopes == nil {
-		r.scopes = []map[string]bool{}
-	}
-
-	r.scopes = append(r.scopes, make(map[string]bool))
+	r.scopes = append(r.scopes, make(map[string]*variable))
 }
 
 func (r *Resolver) endScope() {
@@ -205,18 +326,24 @@ func (r *Resolver) endScope() {
 	}
 }
 
-func (r *Resolver) declare(name token.Token) *loxError.LoxError {
+func (r *Resolver) declare(name token.Token) {
 	if len(r.scopes) == 0 {
-		return nil
+		return
 	}
 
-	if scope, ok := peek(r.scopes); ok {
-		scope[name.Lexeme] = false
-	} else {
-		return loxError.NewRuntimeError(name, fmt.Sprintf("[Line %d]: ", name.Line), "Already a variable with this name in this scope.")
+	scope, _ := peek(r.scopes)
+	if _, exists := scope[name.Lexeme]; exists {
+		r.report(loxError.NewParseError(name, "Already a variable with this name in this scope."))
+		return
 	}
+	scope[name.Lexeme] = &variable{slot: len(scope)}
+}
 
-	return nil
+// declareSynthetic declares an implicit local such as "this" or "super" in
+// the innermost scope.
+func (r *Resolver) declareSynthetic(name string) {
+	scope, _ := peek(r.scopes)
+	scope[name] = &variable{slot: len(scope), defined: true}
 }
 
 func (r *Resolver) define(name token.Token) {
@@ -226,20 +353,16 @@ func (r *Resolver) define(name token.Token) {
 
 	scope, ok := peek(r.scopes)
 	if ok {
-		scope[name.Lexeme] = true
+		scope[name.Lexeme].defined = true
 	}
 }
 
 func (r *Resolver) resolveLocal(expr ast.Expr, name token.Token) {
 	for i := len(r.scopes) - 1; i >= 0; i-- {
-		if _, ok := r.scopes[i][name.Lexeme]; ok {
+		if local, ok := r.scopes[i][name.Lexeme]; ok {
 			depth := len(r.scopes) - 1 - i
-			if i == 0 {
-				loxDebug.LogInfo("Variable '%s' correctly marked as global\n", name.Lexeme)
-			}
-			loxDebug.LogInfo("Resolving variable '%s' as local at depth %d\n", name.Lexeme, depth)
-			r.Interpreter.Resolve(expr, depth)
-			// r.Interpreter.StoreResolution(expr, depth)
+			loxDebug.LogInfo("Resolving variable '%s' as local at depth %d, slot %d\n", name.Lexeme, depth, local.slot)
+			r.Interpreter.Resolve(expr, depth, local.slot)
 			return
 		}
 	}
@@ -257,7 +380,12 @@ func (r *Resolver) VisitVarStmt(stmt *ast.Var) interface{} {
 
 func (r *Resolver) VisitWhileStmt(stmt *ast.While) interface{} {
 	r.resolve(stmt.Condition)
+	r.loopDepth++
 	r.resolve(stmt.Body)
+	r.loopDepth--
+	if stmt.Increment != nil {
+		r.resolve(stmt.Increment)
+	}
 	return nil
 }
 
@@ -265,8 +393,7 @@ func (r *Resolver) VisitAssignExpr(expr *ast.Assign) interface{} {
 	r.resolve(expr.Value)
 
 	if expr.Name.Lexeme == "this" {
-		err := loxError.NewScanError(expr.Name.Line, "Invalid assignment target.")
-		loxError.ReportError(err)
+		return loxError.NewScanError(expr.Name.Line, "Invalid assignment target.")
 	}
 
 	r.resolveLocal(expr, expr.Name)
@@ -289,7 +416,7 @@ func (r *Resolver) VisitCallExpr(expr *ast.Call) interface{} {
 
 func (r *Resolver) VisitGetExpr(expr *ast.Get) interface{} {
 	r.resolve(expr.Object)
-	return nil
+	return r.resolvePrivate(expr, expr.Object, expr.Name)
 }
 
 func (r *Resolver) VisitGroupingExpr(expr *ast.Grouping) interface{} {
@@ -297,6 +424,31 @@ func (r *Resolver) VisitGroupingExpr(expr *ast.Grouping) interface{} {
 	return nil
 }
 
+func (r *Resolver) VisitIndexExpr(expr *ast.Index) interface{} {
+	r.resolve(expr.Object)
+	r.resolve(expr.Index)
+	return nil
+}
+
+func (r *Resolver) VisitIndexSetExpr(expr *ast.IndexSet) interface{} {
+	r.resolve(expr.Value)
+	r.resolve(expr.Object)
+	r.resolve(expr.Index)
+	return nil
+}
+
+func (r *Resolver) VisitLambdaExpr(expr *ast.Lambda) interface{} {
+	r.resolveFunction(expr.Function, FUNCTION)
+	return nil
+}
+
+func (r *Resolver) VisitListExpr(expr *ast.List) interface{} {
+	for _, element := range expr.Elements {
+		r.resolve(element)
+	}
+	return nil
+}
+
 func (r *Resolver) VisitLiteralExpr(expr *ast.Literal) interface{} {
 	return nil
 }
@@ -307,21 +459,45 @@ func (r *Resolver) VisitLogicalExpr(expr *ast.Logical) interface{} {
 	return nil
 }
 
+func (r *Resolver) VisitMapExpr(expr *ast.Map) interface{} {
+	for index, key := range expr.Keys {
+		r.resolve(key)
+		r.resolve(expr.Values[index])
+	}
+	return nil
+}
+
 func (r *Resolver) VisitSetExpr(expr *ast.Set) interface{} {
 	r.resolve(expr.Value)
 	r.resolve(expr.Object)
+	return r.resolvePrivate(expr, expr.Object, expr.Name)
+}
+
+// resolvePrivate checks that a private member is only accessed through
+// 'this', and records the class whose private it is.
+func (r *Resolver) resolvePrivate(expr ast.Expr, object ast.Expr, name token.Token) interface{} {
+	if name.Type != token.PRIVATE_IDENTIFIER {
+		return nil
+	}
+	if r.currentClass == TRAIT {
+		return loxError.NewParseError(name, "Can't use private members in a trait.")
+	}
+	if _, ok := object.(*ast.This); !ok || r.classDecl == nil {
+		return loxError.NewParseError(name, "Private member '"+name.Lexeme+"' can only be accessed through 'this'.")
+	}
+	r.Interpreter.ResolvePrivate(expr, r.classDecl)
 	return nil
 }
 
 func (r *Resolver) VisitSuperExpr(expr *ast.Super) interface{} {
 	if r.currentClass == NOT_CLASS {
-		err := loxError.NewRuntimeError(expr.Keyword, "super", "Can't use 'super' outside of a class.")
-		loxError.ReportAndPanic(err)
-		return nil
+		return loxError.NewRuntimeError(expr.Keyword, "super", "Can't use 'super' outside of a class.")
+	} else if r.currentClass == CLASS_METHOD {
+		return loxError.NewRuntimeError(expr.Keyword, "super", "Can't use 'super' in a class method.")
+	} else if r.currentClass == TRAIT {
+		return loxError.NewRuntimeError(expr.Keyword, "super", "Can't use 'super' in a trait.")
 	} else if r.currentClass != SUBCLASS {
-		err := loxError.NewRuntimeError(expr.Keyword, "super", "Can't use 'super' in a class with no superclass.")
-		loxError.ReportAndPanic(err)
-		return nil
+		return loxError.NewRuntimeError(expr.Keyword, "super", "Can't use 'super' in a class with no superclass.")
 	}
 
 	r.resolveLocal(expr, expr.Keyword)
@@ -332,6 +508,9 @@ func (r *Resolver) VisitThisExpr(expr *ast.This) interface{} {
 	if r.currentClass == NOT_CLASS {
 		return loxError.NewRuntimeError(expr.Keyword, fmt.Sprintf("[Line %d]: ", expr.Keyword.Line), "Can't use 'this' outside of a class.")
 	}
+	if r.currentClass == CLASS_METHOD {
+		return loxError.NewRuntimeError(expr.Keyword, fmt.Sprintf("[Line %d]: ", expr.Keyword.Line), "Can't use 'this' in a class method.")
+	}
 
 	r.resolveLocal(expr, expr.Keyword)
 	return nil
@@ -343,18 +522,10 @@ func (r *Resolver) VisitUnaryExpr(expr *ast.Unary) interface{} {
 }
 
 func (r *Resolver) VisitVariableExpr(expr *ast.Variable) interface{} {
-	loxDebug.LogDebug("Current scopes:", r.scopes)
-
-	if len(r.scopes) > 0 {
-		if scope, ok := peek(r.scopes); ok {
-			if defined, exists := scope[expr.Name.Lexeme]; exists {
-				if !defined {
-					return loxError.NewParseError(expr.Name, "Can't read local variable in its own initializer.")
-				}
-			}
+	if scope, ok := peek(r.scopes); ok {
+		if local, exists := scope[expr.Name.Lexeme]; exists && !local.defined {
+			return loxError.NewParseError(expr.Name, "Can't read local variable in its own initializer.")
 		}
-	} else {
-		panic("No active scope when resolving a variable.")
 	}
 
 	r.resolveLocal(expr, expr.Name)
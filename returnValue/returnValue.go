@@ -1,5 +0,0 @@
-package returnValue
-
-type ReturnValue struct {
-	Value interface{}
-}
@@ -9,22 +9,33 @@ import (
 )
 
 var keywords = map[string]token.TokenType{
-	"and":    token.AND,
-	"class":  token.CLASS,
-	"else":   token.ELSE,
-	"false":  token.FALSE,
-	"for":    token.FOR,
-	"fun":    token.FUN,
-	"if":     token.IF,
-	"nil":    token.NIL,
-	"or":     token.OR,
-	"print":  token.PRINT,
-	"return": token.RETURN,
-	"super":  token.SUPER,
-	"this":   token.THIS,
-	"true":   token.TRUE,
-	"var":    token.VAR,
-	"while":  token.WHILE,
+	"abstract":  token.ABSTRACT,
+	"and":       token.AND,
+	"break":     token.BREAK,
+	"catch":     token.CATCH,
+	"class":     token.CLASS,
+	"continue":  token.CONTINUE,
+	"else":      token.ELSE,
+	"false":     token.FALSE,
+	"finally":   token.FINALLY,
+	"for":       token.FOR,
+	"fun":       token.FUN,
+	"if":        token.IF,
+	"import":    token.IMPORT,
+	"interface": token.INTERFACE,
+	"is":        token.IS,
+	"nil":       token.NIL,
+	"or":        token.OR,
+	"print":     token.PRINT,
+	"return":    token.RETURN,
+	"super":     token.SUPER,
+	"this":      token.THIS,
+	"throw":     token.THROW,
+	"trait":     token.TRAIT,
+	"true":      token.TRUE,
+	"try":       token.TRY,
+	"var":       token.VAR,
+	"while":     token.WHILE,
 }
 
 type Scanner struct {
@@ -76,6 +87,12 @@ func (s *Scanner) scanToken() *loxError.LoxError {
 		s.addToken(token.LEFT_BRACE, nil)
 	case '}':
 		s.addToken(token.RIGHT_BRACE, nil)
+	case '[':
+		s.addToken(token.LEFT_BRACKET, nil)
+	case ']':
+		s.addToken(token.RIGHT_BRACKET, nil)
+	case ':':
+		s.addToken(token.COLON, nil)
 	case ',':
 		s.addToken(token.COMMA, nil)
 	case '.':
@@ -97,6 +114,8 @@ func (s *Scanner) scanToken() *loxError.LoxError {
 	case '=':
 		if s.match('=') {
 			s.addToken(token.EQUAL_EQUAL, nil)
+		} else if s.match('>') {
+			s.addToken(token.ARROW, nil)
 		} else {
 			s.addToken(token.EQUAL, nil)
 		}
@@ -127,10 +146,15 @@ func (s *Scanner) scanToken() *loxError.LoxError {
 		s.Line++
 	case '"':
 		s.string()
-	case 'o':
-		if s.match('r') {
-			s.addToken(token.OR, nil)
+	case '#':
+		// '#' starts the name of a private field or method.
+		if !s.isAlpha(s.peek()) {
+			return loxError.NewScanError(s.Line, "Expect name after '#'.")
 		}
+		for s.isAlphaNumeric(s.peek()) {
+			s.advance()
+		}
+		s.addToken(token.PRIVATE_IDENTIFIER, nil)
 	default:
 		if s.isDigit(c) {
 			s.number()
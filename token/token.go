@@ -10,6 +10,9 @@ const (
 	RIGHT_PAREN
 	LEFT_BRACE
 	RIGHT_BRACE
+	LEFT_BRACKET
+	RIGHT_BRACKET
+	COLON
 	COMMA
 	DOT
 	MINUS
@@ -23,6 +26,7 @@ const (
 	BANG_EQUAL
 	EQUAL
 	EQUAL_EQUAL
+	ARROW
 	GREATER
 	GREATER_EQUAL
 	LESS
@@ -30,24 +34,36 @@ const (
 
 	// Literals.
 	IDENTIFIER
+	PRIVATE_IDENTIFIER
 	STRING
 	NUMBER
 
 	// Keywords
+	ABSTRACT
 	AND
+	BREAK
+	CATCH
 	CLASS
+	CONTINUE
 	ELSE
 	FALSE
+	FINALLY
 	FUN
 	FOR
 	IF
+	IMPORT
+	INTERFACE
+	IS
 	NIL
 	OR
 	PRINT
 	RETURN
 	SUPER
 	THIS
+	THROW
+	TRAIT
 	TRUE
+	TRY
 	VAR
 	WHILE
 
@@ -57,12 +73,14 @@ const (
 func (t TokenType) String() string {
 	names := []string{
 		"LEFT_PAREN", "RIGHT_PAREN", "LEFT_BRACE", "RIGHT_BRACE",
+		"LEFT_BRACKET", "RIGHT_BRACKET", "COLON",
 		"COMMA", "DOT", "MINUS", "PLUS", "SEMICOLON", "SLASH", "STAR",
-		"BANG", "BANG_EQUAL", "EQUAL", "EQUAL_EQUAL",
+		"BANG", "BANG_EQUAL", "EQUAL", "EQUAL_EQUAL", "ARROW",
 		"GREATER", "GREATER_EQUAL", "LESS", "LESS_EQUAL",
-		"IDENTIFIER", "STRING", "NUMBER",
-		"AND", "CLASS", "ELSE", "FALSE", "FUN", "FOR", "IF", "NIL", "OR",
-		"PRINT", "RETURN", "SUPER", "THIS", "TRUE", "VAR", "WHILE", "EOF",
+		"IDENTIFIER", "PRIVATE_IDENTIFIER", "STRING", "NUMBER",
+		"ABSTRACT", "AND", "BREAK", "CATCH", "CLASS", "CONTINUE", "ELSE", "FALSE", "FINALLY",
+		"FUN", "FOR", "IF", "IMPORT", "INTERFACE", "IS", "NIL", "OR", "PRINT", "RETURN", "SUPER", "THIS",
+		"THROW", "TRAIT", "TRUE", "TRY", "VAR", "WHILE", "EOF",
 	}
 
 	if int(t) < len(names) {